@@ -0,0 +1,169 @@
+package tagalign
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/alfatraining/structtag"
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkTags validates the key and value of each tag in the struct by the enabled validators.
+func (w *Helper) checkTags(pass *analysis.Pass, n ast.Node) {
+	if !w.validating() {
+		return
+	}
+
+	v, ok := n.(*ast.StructType)
+	if !ok {
+		return
+	}
+	// the redundant tags of the structs rewritten are removed by the rewrite.
+	rewritten := w.rewriting && w.typeMatched(v)
+
+	for _, field := range v.Fields.List {
+		if field.Tag == nil || ignored(field) {
+			continue
+		}
+
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			// syntax errors are reported when aligning.
+			continue
+		}
+
+		tags, err := structtag.Parse(tag)
+		if err != nil || tags == nil {
+			continue
+		}
+
+		for _, t := range tags.Tags() {
+			loc := tagLocation{field: field, tag: tag, offset: tagValueOffset(tag, t.Key)}
+			w.checkKey(pass, loc, t.Key)
+
+			switch t.Key {
+			case "gorm":
+				if w.gormCheck {
+					w.checkGorm(pass, loc, t.Value)
+				}
+			case "validate", "binding":
+				if w.validateCheck {
+					w.checkValidate(pass, loc, t.Key, t.Value)
+				}
+			}
+		}
+
+		w.checkRedundant(pass, field, tag, tags, !rewritten)
+	}
+}
+
+// validating reports whether any validator of tag keys or values is enabled.
+func (w *Helper) validating() bool {
+	return w.gormCheck || w.validateCheck || len(w.allowedKeys) > 0 || len(w.deniedKeys) > 0 ||
+		len(w.redundantKeys) > 0
+}
+
+// rewrites reports whether tags are rewritten, either by align, sort or the validators.
+func (w *Helper) rewrites() bool {
+	return w.align || w.sort || w.gormCanonical
+}
+
+// rewriteTags rewrites the tag values by the enabled validators, it reports whether any value changed.
+func (w *Helper) rewriteTags(field *ast.Field, tags *structtag.Tags) bool {
+	if tags == nil {
+		return false
+	}
+
+	changed := w.removeRedundantTags(field, tags)
+	for _, t := range tags.Tags() {
+		if t.Key == "gorm" && w.gormCanonical {
+			if v := canonicalGormTag(t.Value); v != t.Value {
+				t.Value = v
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
+
+// tagLocation locates a tag value in the source, so that errors are reported at the offending part of the value.
+type tagLocation struct {
+	field  *ast.Field
+	tag    string // the unquoted tag of the field.
+	offset int    // byte offset of the value in tag, -1 if it can't be mapped.
+}
+
+// reportTagError reports an error located at [offset, offset+length) of the tag value,
+// or at the whole tag if the position can't be mapped to the source.
+func (w *Helper) reportTagError(pass *analysis.Pass, loc tagLocation, offset, length int, category, msg string) {
+	field := loc.field
+	pos, end := field.Tag.Pos(), field.Tag.End()
+
+	start := loc.offset + offset
+	if loc.offset >= 0 && start+length <= len(loc.tag) &&
+		strings.HasPrefix(field.Tag.Value, "`") && !strings.Contains(field.Tag.Value, "\r") {
+		// the content of a raw string is the same as the source.
+		pos = field.Tag.Pos() + 1 + token.Pos(start)
+		end = pos + token.Pos(length)
+	}
+
+	pass.Report(analysis.Diagnostic{
+		Pos:      pos,
+		End:      end,
+		Category: category,
+		Message:  msg,
+	})
+}
+
+// tagValueOffset returns the byte offset of the value of key in tag, or -1 if key is not present.
+// The offset is only returned when the quoted value contains no escape sequence,
+// so that offsets within the unquoted value map to the tag.
+func tagValueOffset(tag, key string) int {
+	for i := 0; i < len(tag); {
+		// skip leading space.
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+
+		// scan to colon.
+		j := i
+		for j < len(tag) && tag[j] > ' ' && tag[j] != ':' && tag[j] != '"' && tag[j] != 0x7f {
+			j++
+		}
+		if j == i || j+1 >= len(tag) || tag[j] != ':' || tag[j+1] != '"' {
+			break
+		}
+		name := tag[i:j]
+
+		// scan quoted string to find value.
+		start := j + 2
+		k := start
+		escaped := false
+		for k < len(tag) && tag[k] != '"' {
+			if tag[k] == '\\' {
+				escaped = true
+				k++
+			}
+			k++
+		}
+		if k >= len(tag) {
+			break
+		}
+
+		if name == key {
+			if escaped {
+				return -1
+			}
+			return start
+		}
+		i = k + 1
+	}
+
+	return -1
+}
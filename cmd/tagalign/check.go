@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/4meepo/tagalign"
+)
+
+// checkMain runs `tagalign check`, which checks the files given by a pre-commit hook.
+// It prints nothing if the files are formatted, and lists the offending files otherwise.
+// It returns 1 if a file isn't formatted, and 2 if a file or its package can't be loaded, or has an invalid tag.
+func checkMain(l *tagalign.Linter, args []string, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("tagalign check", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	a := l.Analyzer()
+	a.Flags.VisitAll(func(f *flag.Flag) {
+		fset.Var(f.Value, f.Name, f.Usage)
+	})
+	fset.Usage = func() {
+		fmt.Fprintf(stderr, "usage: tagalign check [flags] [file ...]\n")
+		fmt.Fprintf(stderr, "The packages of the files are loaded, but only the diagnostics of the files are considered.\n")
+		fset.PrintDefaults()
+	}
+	if err := fset.Parse(args); err != nil {
+		return 2
+	}
+	if !validate(l, stderr) {
+		return 2
+	}
+
+	failed := false
+	// the names of the files given, by absolute name.
+	names := make(map[string]string)
+	var files, patterns []string
+	for _, name := range fset.Args() {
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		if _, err := os.Stat(name); err != nil {
+			fmt.Fprintln(stderr, err)
+			failed = true
+			continue
+		}
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			failed = true
+			continue
+		}
+		if _, ok := names[abs]; !ok {
+			names[abs] = name
+			files = append(files, abs)
+			patterns = append(patterns, "file="+abs)
+		}
+	}
+	if len(patterns) == 0 {
+		return exitCode(failed, nil)
+	}
+
+	pkgs, findings, analysisFailed := analyze(a, patterns, true)
+	failed = failed || analysisFailed
+
+	// the files ignored by the build, e.g. by build constraints, are in no package.
+	loaded := make(map[string]bool)
+	for _, pkg := range pkgs {
+		for _, file := range slices.Concat(pkg.GoFiles, pkg.CompiledGoFiles) {
+			loaded[filepath.Clean(file)] = true
+		}
+	}
+	for _, abs := range files {
+		if !loaded[abs] {
+			fmt.Fprintf(stderr, "tagalign: %s is not in a package loaded\n", names[abs])
+			failed = true
+		}
+	}
+
+	var offending []string
+	for _, f := range findings {
+		filename := f.fset.Position(f.diagnostic.Pos).Filename
+		name, ok := names[filename]
+		if !ok {
+			continue
+		}
+		if invalidTag(f.diagnostic.Category) {
+			failed = true
+		}
+		if !slices.Contains(offending, name) {
+			offending = append(offending, name)
+		}
+	}
+	slices.Sort(offending)
+	for _, name := range offending {
+		fmt.Fprintln(stdout, name)
+	}
+
+	return exitCode(failed, offending)
+}
+
+// invalidTag reports whether the diagnostic of the category is about an invalid tag rather than its style,
+// i.e. the syntax of the tag, the values of gorm and validate, or a malformed tagalign directive.
+func invalidTag(category string) bool {
+	switch category {
+	case tagalign.RuleSyntax, tagalign.RuleGorm, tagalign.RuleValidate, tagalign.RuleDirective:
+		return true
+	}
+	return false
+}
+
+// exitCode returns the exit code of `tagalign check`.
+func exitCode(failed bool, offending []string) int {
+	switch {
+	case failed:
+		return 2
+	case len(offending) > 0:
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckMain(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantOut  string
+		wantErr  string // a substring of the standard error.
+		wantCode int
+	}{
+		{
+			name: "no file",
+			args: []string{"README.md"},
+		},
+		{
+			name: "formatted",
+			args: []string{"testdata/check/formatted.go"},
+		},
+		{
+			name:     "misaligned",
+			args:     []string{"testdata/check/misaligned.go", "testdata/check/formatted.go"},
+			wantOut:  "testdata/check/misaligned.go\n",
+			wantCode: 1,
+		},
+		{
+			name:     "syntax error",
+			args:     []string{"testdata/check/syntax.go", "testdata/check/misaligned.go"},
+			wantOut:  "testdata/check/misaligned.go\ntestdata/check/syntax.go\n",
+			wantCode: 2,
+		},
+		{
+			name:     "invalid gorm tag",
+			args:     []string{"-gorm", "testdata/check/gorm.go"},
+			wantOut:  "testdata/check/gorm.go\n",
+			wantCode: 2,
+		},
+		{
+			name:     "invalid validate tag",
+			args:     []string{"-validate", "testdata/check/validate.go"},
+			wantOut:  "testdata/check/validate.go\n",
+			wantCode: 2,
+		},
+		{
+			name:     "malformed directive",
+			args:     []string{"testdata/check/directive.go"},
+			wantOut:  "testdata/check/directive.go\n",
+			wantCode: 2,
+		},
+		{
+			name:     "missing file",
+			args:     []string{"testdata/check/missing.go", "testdata/check/formatted.go"},
+			wantErr:  "testdata/check/missing.go: no such file or directory",
+			wantCode: 2,
+		},
+		{
+			name:     "file in no package",
+			args:     []string{"testdata/check/ignored.go"},
+			wantErr:  "tagalign: testdata/check/ignored.go is not in a package loaded",
+			wantCode: 2,
+		},
+		{
+			name:     "invalid options",
+			args:     []string{"-strict", "testdata/check/formatted.go"},
+			wantErr:  "tagalign: invalid options: strict style requires align and sort\n",
+			wantCode: 2,
+		},
+		{
+			name:     "invalid flag",
+			args:     []string{"-unknown", "testdata/check/formatted.go"},
+			wantErr:  "flag provided but not defined: -unknown",
+			wantCode: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := checkMain(newLinter(), tt.args, &stdout, &stderr)
+			assert.Equal(t, tt.wantCode, code, stderr.String())
+			assert.Equal(t, tt.wantOut, stdout.String())
+			if tt.wantErr == "" {
+				assert.Empty(t, stderr.String())
+			} else {
+				assert.Contains(t, stderr.String(), tt.wantErr)
+			}
+		})
+	}
+}
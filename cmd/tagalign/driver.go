@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// driver runs the analyzer for the modes which singlechecker doesn't support, i.e. -diff and -format.
+type driver struct {
+	analyzer *analysis.Analyzer
+	flags    *flag.FlagSet
+	diff     bool
+	format   string
+	tests    bool
+}
+
+func newDriver(a *analysis.Analyzer) *driver {
+	d := &driver{analyzer: a}
+	d.flags = flag.NewFlagSet("tagalign", flag.ContinueOnError)
+	d.flags.SetOutput(io.Discard)
+	d.flags.BoolVar(&d.diff, "diff", false, "print the unified diffs of the suggested fixes instead of the diagnostics")
+	d.flags.Bool("fix", false, "ignored with -diff, the files are never updated")
+	d.flags.StringVar(&d.format, "format", "", formatUsage)
+	d.flags.BoolVar(&d.tests, "test", true, "indicates whether test files should be analyzed, too")
+	a.Flags.VisitAll(func(f *flag.Flag) {
+		d.flags.Var(f.Value, f.Name, f.Usage)
+	})
+
+	return d
+}
+
+// parse parses the arguments, it reports whether the driver handles them, i.e. -diff or -format is set.
+// The other arguments, including the flags of singlechecker, are left to singlechecker.
+// It returns an error if -diff or -format is set with a flag the driver doesn't support, e.g. -json.
+func (d *driver) parse(args []string) (bool, error) {
+	if err := d.flags.Parse(args); err != nil {
+		if name, ok := driverFlag(args); ok {
+			return true, fmt.Errorf("tagalign: %w, it's not supported with -%s", err, name)
+		}
+		return false, nil
+	}
+
+	return d.diff || d.format != "", nil
+}
+
+// driverFlag returns the name of the first flag of the driver in the arguments, i.e. -diff or -format.
+func driverFlag(args []string) (string, bool) {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name == "diff" || name == "format" {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// finding is a diagnostic with the file set of its package.
+type finding struct {
+	fset       *token.FileSet
+	diagnostic analysis.Diagnostic
+}
+
+// run analyzes the packages and prints the diffs or the report, it returns the exit code.
+func (d *driver) run() int {
+	if !d.diff && !slices.Contains(formats, d.format) {
+		fmt.Fprintf(os.Stderr, "tagalign: unknown format %q, should be one of %s\n", d.format, strings.Join(formats, ", "))
+		return 2
+	}
+
+	_, findings, failed := analyze(d.analyzer, d.flags.Args(), d.tests)
+	exitCode := 0
+	if failed {
+		exitCode = 1
+	}
+
+	if d.diff {
+		return max(exitCode, printDiffs(os.Stdout, findings))
+	}
+
+	// the text is printed to the standard error like singlechecker.
+	w := io.Writer(os.Stdout)
+	if d.format == "text" {
+		w = os.Stderr
+	}
+
+	return max(exitCode, printReport(w, d.format, findings))
+}
+
+// analyze loads the packages matching the patterns and runs the analyzer on them.
+// It returns the packages loaded, the diagnostics, and whether the packages can't be loaded or analyzed,
+// the errors being printed.
+func analyze(a *analysis.Analyzer, patterns []string, tests bool) ([]*packages.Package, []finding, bool) {
+	cfg := &packages.Config{Mode: packages.LoadSyntax | packages.NeedModule, Tests: tests}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, true
+	}
+	failed := packages.PrintErrors(pkgs) > 0
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{a}, pkgs, nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return pkgs, nil, true
+	}
+
+	var findings []finding
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			fmt.Fprintln(os.Stderr, act.Err)
+			failed = true
+			continue
+		}
+		for _, diagnostic := range act.Diagnostics {
+			findings = append(findings, finding{act.Package.Fset, diagnostic})
+		}
+	}
+
+	return pkgs, findings, failed
+}
+
+// printDiffs prints the unified diffs of the suggested fixes per file, as `-fix` would apply them.
+func printDiffs(w io.Writer, findings []finding) int {
+	// the same file may be analyzed by several packages, e.g. with tests.
+	edits := make(map[*token.File][]analysis.TextEdit)
+	seen := make(map[string]*token.File)
+	for _, f := range findings {
+		if len(f.diagnostic.SuggestedFixes) == 0 {
+			continue
+		}
+		for _, edit := range f.diagnostic.SuggestedFixes[0].TextEdits {
+			tf := f.fset.File(edit.Pos)
+			if prev, ok := seen[tf.Name()]; ok && prev != tf {
+				continue
+			}
+			seen[tf.Name()] = tf
+			edits[tf] = append(edits[tf], edit)
+		}
+	}
+
+	files := make([]*token.File, 0, len(edits))
+	for tf := range edits {
+		files = append(files, tf)
+	}
+	slices.SortFunc(files, func(a, b *token.File) int { return strings.Compare(a.Name(), b.Name()) })
+
+	exitCode := 0
+	for _, tf := range files {
+		src, err := os.ReadFile(tf.Name())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exitCode = 1
+			continue
+		}
+		text, err := unifiedDiff(tf.Name(), src, applyEdits(tf, src, edits[tf]))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exitCode = 1
+			continue
+		}
+		fmt.Fprint(w, text)
+	}
+
+	return exitCode
+}
+
+// unifiedDiff returns the unified diff between the original source of the file and the formatted one, like `gofmt -d`.
+func unifiedDiff(name string, src, res []byte) (string, error) {
+	text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(src),
+		B:        splitLines(res),
+		FromFile: name + ".orig",
+		ToFile:   name,
+		Context:  3,
+	})
+	if err != nil || text == "" {
+		return "", err
+	}
+
+	return fmt.Sprintf("diff -u %s.orig %s\n%s", name, name, text), nil
+}
+
+// splitLines splits the text into lines with their line endings.
+func splitLines(text []byte) []string {
+	lines := strings.SplitAfter(string(text), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// applyEdits applies the edits in order of position, the edits overlapping a previous one are skipped.
+func applyEdits(tf *token.File, src []byte, edits []analysis.TextEdit) []byte {
+	slices.SortStableFunc(edits, func(a, b analysis.TextEdit) int {
+		return int(a.Pos - b.Pos)
+	})
+
+	var buf bytes.Buffer
+	var last int
+	for _, edit := range edits {
+		start, end := tf.Offset(edit.Pos), tf.Offset(edit.End)
+		if start < last {
+			continue
+		}
+		buf.Write(src[last:start])
+		buf.Write(edit.NewText)
+		last = end
+	}
+	buf.Write(src[last:])
+
+	return buf.Bytes()
+}
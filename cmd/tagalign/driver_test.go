@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis"
+)
+
+// fix replaces the first occurrence of old in the source by new, a diagnostic without fix if old is empty.
+type fix struct {
+	old, new string
+}
+
+// newFindings returns the diagnostics of the fixes on the source of the file, with a file set of their own
+// like the diagnostics of a package.
+func newFindings(filename, src string, fixes ...fix) []finding {
+	fset := token.NewFileSet()
+	tf := fset.AddFile(filename, -1, len(src))
+	tf.SetLinesForContent([]byte(src))
+
+	var findings []finding
+	for _, f := range fixes {
+		if f.old == "" {
+			findings = append(findings, finding{fset, analysis.Diagnostic{Pos: tf.Pos(0), Message: "no fix"}})
+			continue
+		}
+		i := strings.Index(src, f.old)
+		edit := analysis.TextEdit{Pos: tf.Pos(i), End: tf.Pos(i + len(f.old)), NewText: []byte(f.new)}
+		findings = append(findings, finding{fset, analysis.Diagnostic{
+			Pos:            edit.Pos,
+			End:            edit.End,
+			Message:        "replace " + f.old,
+			SuggestedFixes: []analysis.SuggestedFix{{Message: "replace " + f.old, TextEdits: []analysis.TextEdit{edit}}},
+		}})
+	}
+
+	return findings
+}
+
+func TestPrintDiffs(t *testing.T) {
+	fooFix := fix{"`yaml:\"foo\" json:\"foo\"`", "`json:\"foo\"     yaml:\"foo\"`"}
+	barFix := fix{"`json:\"foo_bar\" yaml:\"foo_bar\"`", "`json:\"foo_bar\"`"}
+	fooDiff := "diff -u %[1]s.orig %[1]s\n" +
+		"--- %[1]s.orig\n" +
+		"+++ %[1]s\n" +
+		"@@ -1,6 +1,6 @@\n" +
+		" package example\n" +
+		" \n" +
+		" type Example struct {\n" +
+		"-\tFoo    int    `yaml:\"foo\" json:\"foo\"`\n" +
+		"+\tFoo    int    `json:\"foo\"     yaml:\"foo\"`\n" +
+		" \tFooBar string `json:\"foo_bar\" yaml:\"foo_bar\"`\n" +
+		" }\n"
+
+	tests := []struct {
+		name     string
+		packages [][]fix // the fixes of each package analyzing the file.
+		missing  bool    // whether the file is removed before printing the diffs.
+		wantOut  string  // %[1]s is the name of the file.
+		wantCode int
+	}{
+		{
+			name:     "no fix",
+			packages: [][]fix{{{}}},
+		},
+		{
+			name:     "fix",
+			packages: [][]fix{{{}, fooFix}},
+			wantOut:  fooDiff,
+		},
+		{
+			name:     "fixes",
+			packages: [][]fix{{barFix, fooFix}},
+			wantOut: "diff -u %[1]s.orig %[1]s\n" +
+				"--- %[1]s.orig\n" +
+				"+++ %[1]s\n" +
+				"@@ -1,6 +1,6 @@\n" +
+				" package example\n" +
+				" \n" +
+				" type Example struct {\n" +
+				"-\tFoo    int    `yaml:\"foo\" json:\"foo\"`\n" +
+				"-\tFooBar string `json:\"foo_bar\" yaml:\"foo_bar\"`\n" +
+				"+\tFoo    int    `json:\"foo\"     yaml:\"foo\"`\n" +
+				"+\tFooBar string `json:\"foo_bar\"`\n" +
+				" }\n",
+		},
+		{
+			name:     "overlapping fixes",
+			packages: [][]fix{{fooFix, {"json:\"foo\"", "json:\"bar\""}}},
+			wantOut:  fooDiff,
+		},
+		{
+			name:     "several packages",
+			packages: [][]fix{{fooFix}, {fooFix}},
+			wantOut:  fooDiff,
+		},
+		{
+			name:     "missing file",
+			packages: [][]fix{{fooFix}},
+			missing:  true,
+			wantCode: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "example.go")
+			require.NoError(t, os.WriteFile(filename, []byte(unformattedSrc), 0o644))
+
+			var findings []finding
+			for _, fixes := range tt.packages {
+				findings = append(findings, newFindings(filename, unformattedSrc, fixes...)...)
+			}
+			if tt.missing {
+				require.NoError(t, os.Remove(filename))
+			}
+
+			var stdout bytes.Buffer
+			assert.Equal(t, tt.wantCode, printDiffs(&stdout, findings))
+			assert.Equal(t, format(tt.wantOut, filename), stdout.String())
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/4meepo/tagalign"
+	"golang.org/x/mod/modfile"
+)
+
+// formatMain runs the gofmt-style mode `tagalign fmt`, which parses the files without loading their packages,
+// so it works on code which doesn't compile or is outside of a module. It returns the exit code.
+func formatMain(l *tagalign.Linter, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fset := flag.NewFlagSet("tagalign fmt", flag.ContinueOnError)
+	fset.SetOutput(stderr)
+	write := fset.Bool("w", false, "write result to (source) file instead of stdout")
+	list := fset.Bool("l", false, "list files whose tags are not formatted")
+	diff := fset.Bool("d", false, "display diffs instead of rewriting files")
+	stdinFilename := fset.String("stdin-filename", "stdin.go",
+		"name of the source read from standard input, used to find configuration files and match file patterns")
+	l.Analyzer().Flags.VisitAll(func(f *flag.Flag) {
+		fset.Var(f.Value, f.Name, f.Usage)
+	})
+	fset.Usage = func() {
+		fmt.Fprintf(stderr, "usage: tagalign fmt [flags] [path ...]\n")
+		fmt.Fprintf(stderr, "Without a path, the source is read from standard input and written to standard output.\n")
+		fset.PrintDefaults()
+	}
+	if err := fset.Parse(args); err != nil {
+		return 2
+	}
+	if !validate(l, stderr) {
+		return 2
+	}
+
+	f := &formatter{linter: l, stdout: stdout, stderr: stderr, write: *write, list: *list, diff: *diff}
+	if fset.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(stderr, "tagalign: cannot use -w with standard input")
+			return 2
+		}
+		if err := f.formatStdin(stdin, *stdinFilename); err != nil {
+			f.report(err)
+		}
+	}
+	for _, path := range fset.Args() {
+		f.formatPath(path)
+	}
+	if f.failed {
+		return 2
+	}
+
+	return 0
+}
+
+type formatter struct {
+	linter *tagalign.Linter
+	stdout io.Writer
+	stderr io.Writer
+	write  bool // whether write the result to the file.
+	list   bool // whether list the files not formatted.
+	diff   bool // whether print the diffs.
+	failed bool // whether an error is reported.
+}
+
+func (f *formatter) report(err error) {
+	fmt.Fprintln(f.stderr, err)
+	f.failed = true
+}
+
+// formatPath formats the file, or the Go files in the directory recursively.
+func (f *formatter) formatPath(path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		f.report(err)
+		return
+	}
+	if !info.IsDir() {
+		if err := f.formatFile(path); err != nil {
+			f.report(err)
+		}
+		return
+	}
+
+	err = filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			f.report(err)
+			return nil
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") || !strings.HasSuffix(d.Name(), ".go") {
+			return nil
+		}
+
+		if err := f.formatFile(path); err != nil {
+			f.report(err)
+		}
+		return nil
+	})
+	if err != nil {
+		f.report(err)
+	}
+}
+
+// formatStdin formats the source read from standard input, filename is the name of the source.
+func (f *formatter) formatStdin(stdin io.Reader, filename string) error {
+	src, err := io.ReadAll(stdin)
+	if err != nil {
+		return err
+	}
+
+	return f.format(filename, "<standard input>", src)
+}
+
+func (f *formatter) formatFile(filename string) error {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	return f.format(filename, filename, src)
+}
+
+// format formats the source of the file, name is the name of the source in the output.
+func (f *formatter) format(filename, name string, src []byte) error {
+	res, err := formatSource(f.linter, filename, name, src)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(src, res) {
+		if f.list {
+			fmt.Fprintln(f.stdout, name)
+		}
+		if f.write {
+			info, err := os.Stat(filename)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filename, res, info.Mode().Perm()); err != nil {
+				return err
+			}
+		}
+		if f.diff {
+			text, err := unifiedDiff(name, src, res)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(f.stdout, text)
+		}
+	}
+
+	if !f.list && !f.write && !f.diff {
+		_, err = f.stdout.Write(res)
+	}
+
+	return err
+}
+
+// formatSource formats the source only, without type information, by the options the analyzer runs with.
+// The file name is used to find the configuration files and match the file patterns,
+// the errors of the source are reported by its name, e.g. <standard input>.
+func formatSource(l *tagalign.Linter, filename, name string, src []byte) ([]byte, error) {
+	// the absolute name is used to find configuration files.
+	filename, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		if list, ok := err.(scanner.ErrorList); ok {
+			for _, e := range list {
+				e.Pos.Filename = name
+			}
+		}
+		return nil, err
+	}
+
+	options, err := l.Options(filename)
+	if err != nil {
+		return nil, err
+	}
+	edits, err := tagalign.FormatFile(fset, file, importPath(filepath.Dir(filename)), options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return tagalign.ApplyEdits(fset.File(file.Pos()), src, edits), nil
+}
+
+// importPath returns the import path of the package in the directory, by the module path of the nearest go.mod,
+// so that the package filters apply as when the package is loaded. It returns "" outside of a module.
+func importPath(dir string) string {
+	for root := dir; ; root = filepath.Dir(root) {
+		data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+		if err == nil {
+			modulePath := modfile.ModulePath(data)
+			rel, err := filepath.Rel(root, dir)
+			if modulePath == "" || err != nil {
+				return ""
+			}
+			return path.Join(modulePath, filepath.ToSlash(rel))
+		}
+		if filepath.Dir(root) == root {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const unformattedSrc = "package example\n\n" +
+	"type Example struct {\n" +
+	"\tFoo    int    `yaml:\"foo\" json:\"foo\"`\n" +
+	"\tFooBar string `json:\"foo_bar\" yaml:\"foo_bar\"`\n" +
+	"}\n"
+
+const formattedSrc = "package example\n\n" +
+	"type Example struct {\n" +
+	"\tFoo    int    `json:\"foo\"     yaml:\"foo\"`\n" +
+	"\tFooBar string `json:\"foo_bar\" yaml:\"foo_bar\"`\n" +
+	"}\n"
+
+func TestFormatMain(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		src      string
+		config   string // the configuration file in the directory of the file, if any.
+		wantOut  string // %[1]s is the name of the file.
+		wantSrc  string // the source of the file after formatting.
+		wantErr  string
+		wantCode int
+	}{
+		{
+			name:    "print",
+			args:    []string{"-sort"},
+			src:     unformattedSrc,
+			wantOut: formattedSrc,
+			wantSrc: unformattedSrc,
+		},
+		{
+			name:    "list",
+			args:    []string{"-sort", "-l"},
+			src:     unformattedSrc,
+			wantOut: "%[1]s\n",
+			wantSrc: unformattedSrc,
+		},
+		{
+			name:    "list formatted",
+			args:    []string{"-sort", "-l"},
+			src:     formattedSrc,
+			wantSrc: formattedSrc,
+		},
+		{
+			name:    "write",
+			args:    []string{"-sort", "-w"},
+			src:     unformattedSrc,
+			wantSrc: formattedSrc,
+		},
+		{
+			name:    "list and write",
+			args:    []string{"-sort", "-l", "-w"},
+			src:     unformattedSrc,
+			wantOut: "%[1]s\n",
+			wantSrc: formattedSrc,
+		},
+		{
+			name: "diff",
+			args: []string{"-sort", "-d"},
+			src:  unformattedSrc,
+			wantOut: "diff -u %[1]s.orig %[1]s\n" +
+				"--- %[1]s.orig\n" +
+				"+++ %[1]s\n" +
+				"@@ -1,6 +1,6 @@\n" +
+				" package example\n" +
+				" \n" +
+				" type Example struct {\n" +
+				"-\tFoo    int    `yaml:\"foo\" json:\"foo\"`\n" +
+				"+\tFoo    int    `json:\"foo\"     yaml:\"foo\"`\n" +
+				" \tFooBar string `json:\"foo_bar\" yaml:\"foo_bar\"`\n" +
+				" }\n",
+			wantSrc: unformattedSrc,
+		},
+		{
+			name:    "align only",
+			args:    []string{"-l"},
+			src:     unformattedSrc,
+			wantOut: "%[1]s\n",
+			wantSrc: unformattedSrc,
+		},
+		{
+			name:    "redundant without align",
+			args:    []string{"-align=false", "-redundant=json"},
+			src:     "package example\n\ntype Example struct {\n\tFoo int `json:\"Foo\"`\n}\n",
+			wantOut: "package example\n\ntype Example struct {\n\tFoo int\n}\n",
+			wantSrc: "package example\n\ntype Example struct {\n\tFoo int `json:\"Foo\"`\n}\n",
+		},
+		{
+			name:     "syntax error",
+			args:     []string{"-sort", "-w"},
+			src:      "package example\n\ntype Example struct {\n",
+			wantSrc:  "package example\n\ntype Example struct {\n",
+			wantErr:  "%[1]s:3:23: expected '}', found 'EOF'\n",
+			wantCode: 2,
+		},
+		{
+			name:     "invalid options",
+			args:     []string{"-strict", "-sort=false"},
+			src:      unformattedSrc,
+			wantSrc:  unformattedSrc,
+			wantErr:  "tagalign: invalid options: strict style requires align and sort\n",
+			wantCode: 2,
+		},
+		{
+			name:     "invalid configuration",
+			args:     []string{"-sort=false"},
+			config:   "strict: true\n",
+			src:      unformattedSrc,
+			wantSrc:  unformattedSrc,
+			wantErr:  "invalid options for %[1]s: strict style requires align and sort\n",
+			wantCode: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			filename := filepath.Join(dir, "example.go")
+			require.NoError(t, os.WriteFile(filename, []byte(tt.src), 0o644))
+			if tt.config != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, ".tagalign.yaml"), []byte(tt.config), 0o644))
+			}
+
+			var stdout, stderr bytes.Buffer
+			code := formatMain(newLinter(), append(tt.args, filename), strings.NewReader(""), &stdout, &stderr)
+			assert.Equal(t, tt.wantCode, code)
+			assert.Equal(t, format(tt.wantOut, filename), stdout.String())
+			assert.Equal(t, format(tt.wantErr, filename), stderr.String())
+
+			src, err := os.ReadFile(filename)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSrc, string(src))
+		})
+	}
+}
+
+func TestFormatMain_directory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"example.go":          unformattedSrc,
+		"formatted.go":        formattedSrc,
+		"sub/example.go":      unformattedSrc,
+		"sub/.example.go":     unformattedSrc,
+		"sub/example.go.orig": unformattedSrc,
+	}
+	for name, src := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644))
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := formatMain(newLinter(), []string{"-sort", "-l", dir, filepath.Join(dir, "missing.go")},
+		strings.NewReader(""), &stdout, &stderr)
+	assert.Equal(t, 2, code)
+	assert.Equal(t, filepath.Join(dir, "example.go")+"\n"+filepath.Join(dir, "sub", "example.go")+"\n", stdout.String())
+	assert.Contains(t, stderr.String(), "missing.go: no such file or directory")
+}
+
+// format replaces %[1]s in the expected output by the name of the file.
+func format(want, filename string) string {
+	return strings.ReplaceAll(want, "%[1]s", filename)
+}
+
+func TestFormatMain_stdin(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		wantOut  string
+		wantErr  string
+		wantCode int
+	}{
+		{
+			name:    "format",
+			args:    []string{"-sort"},
+			stdin:   unformattedSrc,
+			wantOut: formattedSrc,
+		},
+		{
+			name:    "formatted",
+			args:    []string{"-sort"},
+			stdin:   formattedSrc,
+			wantOut: formattedSrc,
+		},
+		{
+			name:    "list",
+			args:    []string{"-sort", "-l"},
+			stdin:   unformattedSrc,
+			wantOut: "<standard input>\n",
+		},
+		{
+			name:  "diff",
+			args:  []string{"-sort", "-d"},
+			stdin: unformattedSrc,
+			wantOut: "diff -u <standard input>.orig <standard input>\n" +
+				"--- <standard input>.orig\n" +
+				"+++ <standard input>\n" +
+				"@@ -1,6 +1,6 @@\n" +
+				" package example\n" +
+				" \n" +
+				" type Example struct {\n" +
+				"-\tFoo    int    `yaml:\"foo\" json:\"foo\"`\n" +
+				"+\tFoo    int    `json:\"foo\"     yaml:\"foo\"`\n" +
+				" \tFooBar string `json:\"foo_bar\" yaml:\"foo_bar\"`\n" +
+				" }\n",
+		},
+		{
+			name:    "stdin filename excluded",
+			args:    []string{"-sort", "-exclude-files", "*_gen.go", "-stdin-filename", "example_gen.go"},
+			stdin:   unformattedSrc,
+			wantOut: unformattedSrc,
+		},
+		{
+			name:     "write",
+			args:     []string{"-sort", "-w"},
+			stdin:    unformattedSrc,
+			wantErr:  "tagalign: cannot use -w with standard input\n",
+			wantCode: 2,
+		},
+		{
+			name:     "syntax error",
+			args:     []string{"-sort"},
+			stdin:    "package example\n\ntype Example struct {\n",
+			wantErr:  "<standard input>:3:23: expected '}', found 'EOF'\n",
+			wantCode: 2,
+		},
+		{
+			name:     "syntax error with stdin filename",
+			args:     []string{"-sort", "-stdin-filename", "example.go"},
+			stdin:    "package example\n\ntype Example struct {\n",
+			wantErr:  "<standard input>:3:23: expected '}', found 'EOF'\n",
+			wantCode: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := formatMain(newLinter(), tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			assert.Equal(t, tt.wantCode, code)
+			assert.Equal(t, tt.wantOut, stdout.String())
+			assert.Equal(t, tt.wantErr, stderr.String())
+		})
+	}
+}
+
+func TestFormatMain_packages(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":              "module example.com/m\n\ngo 1.22\n",
+		"api/example.go":      unformattedSrc,
+		"internal/db/user.go": unformattedSrc,
+	}
+	for name, src := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644))
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantOut string
+	}{
+		{
+			name:    "exclude",
+			args:    []string{"-exclude-packages", "/internal/"},
+			wantOut: filepath.Join(dir, "api", "example.go") + "\n",
+		},
+		{
+			name:    "include",
+			args:    []string{"-include-packages", "^example.com/m/internal/"},
+			wantOut: filepath.Join(dir, "internal", "db", "user.go") + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := formatMain(newLinter(), append(tt.args, "-sort", "-l", dir), strings.NewReader(""), &stdout, &stderr)
+			assert.Equal(t, 0, code, stderr.String())
+			assert.Equal(t, tt.wantOut, stdout.String())
+		})
+	}
+}
+
+func Test_importPath(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n"), 0o644))
+
+	assert.Equal(t, "example.com/m", importPath(dir))
+	assert.Equal(t, "example.com/m/internal/db", importPath(filepath.Join(dir, "internal", "db")))
+	assert.Equal(t, "", importPath(filepath.Dir(dir)))
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/4meepo/tagalign"
+	"gopkg.in/yaml.v3"
+)
+
+// inferredConfig is the configuration file printed by `tagalign infer`.
+type inferredConfig struct {
+	Sort      bool               `yaml:"sort"`
+	Order     []string           `yaml:"order,flow"`
+	Overrides []inferredOverride `yaml:"overrides,omitempty"`
+}
+
+type inferredOverride struct {
+	Paths []string `yaml:"paths,flow"`
+	Order []string `yaml:"order,flow"`
+}
+
+// inferMain runs `tagalign infer`, which infers the order of the tag keys from the code
+// and prints a configuration file sorting the tags by it. It returns the exit code.
+func inferMain(args []string) int {
+	c := newStatsCollector()
+	fset := c.flagSet("infer", "Infer the order of the tag keys from the code and print a configuration file sorting the tags by it.")
+	output := fset.String("o", "", "write the configuration file, e.g. .tagalign.yaml, instead of printing it")
+	tests := fset.Bool("test", true, "indicates whether test files should be analyzed, too")
+	if err := fset.Parse(args); err != nil {
+		return 2
+	}
+	if !validate(c.linter, os.Stderr) {
+		return 2
+	}
+
+	exitCode := c.run(fset.Args(), *tests)
+
+	// the paths of the overrides are relative to the configuration file.
+	dir, err := filepath.Abs(filepath.Dir(*output))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	var total tagalign.Stats
+	var names []string
+	dirs := make(map[string]*tagalign.Stats)
+	for _, f := range c.files {
+		total.Add(f.stats)
+		d := filepath.Dir(f.filename)
+		if dirs[d] == nil {
+			dirs[d] = &tagalign.Stats{}
+			names = append(names, d)
+		}
+		dirs[d].Add(f.stats)
+	}
+	slices.Sort(names)
+
+	cfg := inferredConfig{Sort: true, Order: total.InferOrder()}
+	for _, d := range names {
+		rel, err := filepath.Rel(dir, d)
+		if err != nil || strings.HasPrefix(rel, "..") || dirs[d].Agrees(cfg.Order) {
+			continue
+		}
+		cfg.Overrides = append(cfg.Overrides, inferredOverride{
+			Paths: []string{filepath.ToSlash(filepath.Join(rel, "*.go"))},
+			Order: dirs[d].InferOrder(),
+		})
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by tagalign infer, the order of the tag keys agreeing the most with the code.\n")
+	fmt.Fprintf(&buf, "# The overrides are the directories whose fields follow another order.\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+	} else {
+		err = os.WriteFile(*output, buf.Bytes(), 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return exitCode
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+var formats = []string{"text", "json", "sarif", "checkstyle", "github-actions"}
+
+const formatUsage = "print the diagnostics in the given format: text, json, sarif, checkstyle or github-actions"
+
+// issue is a diagnostic resolved to positions, as printed by the reports.
+type issue struct {
+	File         string        `json:"file"`
+	Line         int           `json:"line"`
+	Column       int           `json:"column"`
+	EndLine      int           `json:"endLine"`
+	EndColumn    int           `json:"endColumn"`
+	Rule         string        `json:"rule"`
+	Message      string        `json:"message"`
+	Replacements []replacement `json:"replacements,omitempty"`
+}
+
+// replacement is an edit of the suggested fix of an issue.
+type replacement struct {
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"endLine"`
+	EndColumn int    `json:"endColumn"`
+	Text      string `json:"text"`
+}
+
+func newIssues(findings []finding) []issue {
+	issues := make([]issue, 0, len(findings))
+	for _, f := range findings {
+		d := f.diagnostic
+		pos, end := f.fset.Position(d.Pos), f.fset.Position(d.Pos)
+		if d.End.IsValid() {
+			end = f.fset.Position(d.End)
+		}
+
+		i := issue{
+			File:      pos.Filename,
+			Line:      pos.Line,
+			Column:    pos.Column,
+			EndLine:   end.Line,
+			EndColumn: end.Column,
+			Rule:      cmp.Or(d.Category, "tagalign"),
+			Message:   d.Message,
+		}
+		if len(d.SuggestedFixes) > 0 {
+			for _, edit := range d.SuggestedFixes[0].TextEdits {
+				start, end := f.fset.Position(edit.Pos), f.fset.Position(edit.End)
+				i.Replacements = append(i.Replacements, replacement{
+					Line:      start.Line,
+					Column:    start.Column,
+					EndLine:   end.Line,
+					EndColumn: end.Column,
+					Text:      string(edit.NewText),
+				})
+			}
+		}
+		issues = append(issues, i)
+	}
+
+	// the same file may be analyzed by several packages, e.g. with tests.
+	slices.SortStableFunc(issues, func(a, b issue) int {
+		return cmp.Or(strings.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column),
+			strings.Compare(a.Message, b.Message))
+	})
+	issues = slices.CompactFunc(issues, func(a, b issue) bool {
+		return a.File == b.File && a.Line == b.Line && a.Column == b.Column && a.Message == b.Message
+	})
+
+	return issues
+}
+
+// printReport prints the diagnostics in the format, it returns the exit code:
+// 3 if there are diagnostics like singlechecker, whatever the format.
+func printReport(w io.Writer, format string, findings []finding) int {
+	issues := newIssues(findings)
+
+	var err error
+	switch format {
+	case "text":
+		err = printText(w, issues)
+	case "json":
+		err = printJSON(w, issues)
+	case "sarif":
+		err = printSARIF(w, issues)
+	case "checkstyle":
+		err = printCheckstyle(w, issues)
+	case "github-actions":
+		err = printGitHubActions(w, issues)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if len(issues) > 0 {
+		return 3
+	}
+
+	return 0
+}
+
+func printText(w io.Writer, issues []issue) error {
+	for _, i := range issues {
+		if _, err := fmt.Fprintf(w, "%s:%d:%d: %s (%s)\n", i.File, i.Line, i.Column, i.Message, i.Rule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func printJSON(w io.Writer, issues []issue) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+
+	return enc.Encode(issues)
+}
+
+// printGitHubActions prints the issues as workflow commands, which annotate the files in GitHub Actions.
+func printGitHubActions(w io.Writer, issues []issue) error {
+	escape := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	escapeProperty := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+	for _, i := range issues {
+		_, err := fmt.Fprintf(w, "::error file=%s,line=%d,col=%d,endLine=%d,endColumn=%d,title=%s::%s\n",
+			escapeProperty.Replace(relativePath(i.File)), i.Line, i.Column, i.EndLine, i.EndColumn,
+			escapeProperty.Replace("tagalign "+i.Rule), escape.Replace(i.Message))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func printCheckstyle(w io.Writer, issues []issue) error {
+	type checkstyleError struct {
+		Line     int    `xml:"line,attr"`
+		Column   int    `xml:"column,attr"`
+		Severity string `xml:"severity,attr"`
+		Message  string `xml:"message,attr"`
+		Source   string `xml:"source,attr"`
+	}
+	type checkstyleFile struct {
+		Name   string            `xml:"name,attr"`
+		Errors []checkstyleError `xml:"error"`
+	}
+	type checkstyle struct {
+		XMLName xml.Name          `xml:"checkstyle"`
+		Version string            `xml:"version,attr"`
+		Files   []*checkstyleFile `xml:"file"`
+	}
+
+	report := checkstyle{Version: "5.0"}
+	for _, i := range issues {
+		if len(report.Files) == 0 || report.Files[len(report.Files)-1].Name != i.File {
+			report.Files = append(report.Files, &checkstyleFile{Name: i.File})
+		}
+		file := report.Files[len(report.Files)-1]
+		file.Errors = append(file.Errors, checkstyleError{
+			Line:     i.Line,
+			Column:   i.Column,
+			Severity: "error",
+			Message:  i.Message,
+			Source:   "tagalign." + i.Rule,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// printSARIF prints the issues as a SARIF 2.1.0 log, with the suggested fixes.
+func printSARIF(w io.Writer, issues []issue) error {
+	type sarifMessage struct {
+		Text string `json:"text"`
+	}
+	type sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+	type sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+		EndLine     int `json:"endLine"`
+		EndColumn   int `json:"endColumn"`
+	}
+	type sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           sarifRegion           `json:"region"`
+	}
+	type sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+	type sarifReplacement struct {
+		DeletedRegion   sarifRegion  `json:"deletedRegion"`
+		InsertedContent sarifMessage `json:"insertedContent"`
+	}
+	type sarifArtifactChange struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Replacements     []sarifReplacement    `json:"replacements"`
+	}
+	type sarifFix struct {
+		Description     sarifMessage          `json:"description"`
+		ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+	}
+	type sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+		Fixes     []sarifFix      `json:"fixes,omitempty"`
+	}
+	type sarifRule struct {
+		ID string `json:"id"`
+	}
+	type sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+	type sarifRun struct {
+		Tool struct {
+			Driver sarifDriver `json:"driver"`
+		} `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+	type sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	run := sarifRun{Results: []sarifResult{}}
+	run.Tool.Driver = sarifDriver{Name: "tagalign", InformationURI: "https://github.com/4meepo/tagalign", Rules: []sarifRule{}}
+	for _, i := range issues {
+		if !slices.Contains(run.Tool.Driver.Rules, sarifRule{i.Rule}) {
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{i.Rule})
+		}
+
+		location := sarifArtifactLocation{URI: filepath.ToSlash(relativePath(i.File))}
+		result := sarifResult{
+			RuleID:  i.Rule,
+			Level:   "error",
+			Message: sarifMessage{i.Message},
+			Locations: []sarifLocation{{sarifPhysicalLocation{
+				ArtifactLocation: location,
+				Region:           sarifRegion{i.Line, i.Column, i.EndLine, i.EndColumn},
+			}}},
+		}
+		if len(i.Replacements) > 0 {
+			change := sarifArtifactChange{ArtifactLocation: location}
+			for _, r := range i.Replacements {
+				change.Replacements = append(change.Replacements, sarifReplacement{
+					DeletedRegion:   sarifRegion{r.Line, r.Column, r.EndLine, r.EndColumn},
+					InsertedContent: sarifMessage{r.Text},
+				})
+			}
+			result.Fixes = []sarifFix{{Description: sarifMessage{i.Message}, ArtifactChanges: []sarifArtifactChange{change}}}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// relativePath returns the path relative to the working directory if it's inside of it.
+func relativePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+
+	return rel
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/4meepo/tagalign"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportFindings returns the findings printed by the golden reports, from two packages analyzing the same file.
+func reportFindings() []finding {
+	var findings []finding
+	for range 2 {
+		findings = append(findings, newFindings("testdata/b.go", unformattedSrc,
+			fix{"`yaml:\"foo\" json:\"foo\"`", "`json:\"foo\"     yaml:\"foo\"`"})...)
+	}
+	for i := range findings {
+		findings[i].diagnostic.Category = tagalign.RuleOrder
+		findings[i].diagnostic.Message = `tag is not aligned, should be: json:"foo"     yaml:"foo"`
+	}
+
+	src := "package example\n\ntype Example struct {\n\tID int `gorm:\"primaryKey:yes\" json:\"id,omitempty\"`\n}\n"
+	fset := token.NewFileSet()
+	tf := fset.AddFile("testdata/a.go", -1, len(src))
+	tf.SetLinesForContent([]byte(src))
+	pos := tf.LineStart(4) + 8
+	findings = append(findings,
+		finding{fset, analysis.Diagnostic{
+			Pos:      pos + 6,
+			End:      pos + 20,
+			Category: tagalign.RuleGorm,
+			Message:  `gorm directive "primaryKey" requires a boolean value, got "yes"`,
+		}},
+		finding{fset, analysis.Diagnostic{
+			Pos:     pos,
+			Message: "100% unknown, with a comma: and a colon\non two lines",
+		}},
+	)
+
+	return findings
+}
+
+func TestPrintReport(t *testing.T) {
+	for _, format := range formats {
+		t.Run(format, func(t *testing.T) {
+			var out bytes.Buffer
+			assert.Equal(t, 3, printReport(&out, format, reportFindings()))
+
+			golden, err := os.ReadFile(filepath.Join("testdata", "report."+format+".golden"))
+			require.NoError(t, err)
+			assert.Equal(t, string(golden), out.String())
+		})
+	}
+}
+
+func TestPrintReport_empty(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantOut string
+	}{
+		{"text", ""},
+		{"json", "[]\n"},
+		{"github-actions", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var out bytes.Buffer
+			assert.Equal(t, 0, printReport(&out, tt.format, nil))
+			assert.Equal(t, tt.wantOut, out.String())
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/4meepo/tagalign"
+	"golang.org/x/tools/go/packages"
+)
+
+// packageStats is the stats of the files of a package.
+type packageStats struct {
+	Package string `json:"package"`
+	tagalign.Stats
+}
+
+// fileStats is the stats of a file of a package.
+type fileStats struct {
+	pkgPath  string
+	filename string
+	stats    tagalign.Stats
+}
+
+// statsCollector collects the stats of the files of the packages by the options of its linter.
+type statsCollector struct {
+	linter *tagalign.Linter
+	files  []fileStats
+	seen   map[string]bool // the files collected, which may be in several packages with tests.
+}
+
+func newStatsCollector() *statsCollector {
+	return &statsCollector{linter: newLinter(), seen: make(map[string]bool)}
+}
+
+// run parses the packages and collects the stats of their files, it returns the exit code.
+func (c *statsCollector) run(patterns []string, tests bool) int {
+	// the packages are parsed only, so that the stats of code which doesn't compile are collected too.
+	mode := packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedSyntax | packages.NeedModule
+	pkgs, err := packages.Load(&packages.Config{Mode: mode, Tests: tests}, patterns...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	exitCode := 0
+	if packages.PrintErrors(pkgs) > 0 {
+		exitCode = 1
+	}
+	for _, pkg := range pkgs {
+		if err := c.collectPackage(pkg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exitCode = 1
+		}
+	}
+
+	return exitCode
+}
+
+// flagSet returns the flag set of the subcommand, with the flags of the analyzer.
+func (c *statsCollector) flagSet(name, usage string) *flag.FlagSet {
+	fset := flag.NewFlagSet("tagalign "+name, flag.ContinueOnError)
+	c.linter.Analyzer().Flags.VisitAll(func(f *flag.Flag) {
+		fset.Var(f.Value, f.Name, f.Usage)
+	})
+	fset.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: tagalign %s [flags] [package ...]\n%s\n", name, usage)
+		fset.PrintDefaults()
+	}
+
+	return fset
+}
+
+// statsMain runs `tagalign stats`, which prints the stats of the struct tags of each package,
+// to measure the impact of a style before enabling it. It returns the exit code.
+func statsMain(args []string) int {
+	c := newStatsCollector()
+	fset := c.flagSet("stats", "Print the stats of the struct tags of each package.")
+	asJSON := fset.Bool("json", false, "print the stats in JSON")
+	top := fset.Int("top", 5, "number of the most common tag keys and orders printed, all if negative")
+	tests := fset.Bool("test", true, "indicates whether test files should be analyzed, too")
+	if err := fset.Parse(args); err != nil {
+		return 2
+	}
+	if !validate(c.linter, os.Stderr) {
+		return 2
+	}
+
+	exitCode := c.run(fset.Args(), *tests)
+
+	// the test variants of a package are counted with it.
+	var stats []*packageStats
+	for _, f := range c.files {
+		i := slices.IndexFunc(stats, func(s *packageStats) bool { return s.Package == f.pkgPath })
+		if i < 0 {
+			stats = append(stats, &packageStats{Package: f.pkgPath})
+			i = len(stats) - 1
+		}
+		stats[i].Add(f.stats)
+	}
+	slices.SortFunc(stats, func(a, b *packageStats) int { return strings.Compare(a.Package, b.Package) })
+
+	var err error
+	if *asJSON {
+		err = printStatsJSON(os.Stdout, stats)
+	} else {
+		err = printStats(os.Stdout, stats, *top)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return exitCode
+}
+
+// collectPackage collects the stats of the files of the package, by the options the linter runs with for each file.
+func (c *statsCollector) collectPackage(pkg *packages.Package) error {
+	for _, file := range pkg.Syntax {
+		filename := pkg.Fset.File(file.Pos()).Name()
+		if !strings.HasSuffix(filename, ".go") || c.seen[filename] {
+			continue
+		}
+		c.seen[filename] = true
+
+		options, err := c.linter.Options(filename)
+		if err != nil {
+			return err
+		}
+		s, err := tagalign.FileStats(pkg.Fset, file, pkg.PkgPath, options...)
+		if err != nil {
+			return err
+		}
+		c.files = append(c.files, fileStats{pkg.PkgPath, filename, s})
+	}
+
+	return nil
+}
+
+func printStatsJSON(w io.Writer, stats []*packageStats) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+
+	return enc.Encode(stats)
+}
+
+// printStats prints the stats of each package, then the total if there are several packages.
+func printStats(w io.Writer, stats []*packageStats, top int) error {
+	var total tagalign.Stats
+	for _, s := range stats {
+		total.Add(s.Stats)
+	}
+	if len(stats) > 1 {
+		stats = append(stats, &packageStats{Package: "total", Stats: total})
+	}
+
+	counts := func(counts []tagalign.Count) string {
+		if len(counts) == 0 {
+			return "none"
+		}
+		s := make([]string, len(counts))
+		for i, c := range counts {
+			s[i] = fmt.Sprintf("%s (%d)", c.Name, c.Count)
+		}
+		return strings.Join(s, ", ")
+	}
+	for i, s := range stats {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		_, err := fmt.Fprintf(w, "%s\n"+
+			"\tstructs:           %d\n"+
+			"\tgroups:            %d\n"+
+			"\tmisaligned groups: %d\n"+
+			"\tmisordered tags:   %d\n"+
+			"\tsyntax errors:     %d\n"+
+			"\tkeys:              %s\n"+
+			"\torders:            %s\n",
+			s.Package, s.Structs, s.Groups, s.MisalignedGroups, s.MisorderedTags, s.SyntaxErrors,
+			counts(s.TopKeys(top)), counts(s.TopOrders(top)))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
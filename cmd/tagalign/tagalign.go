@@ -2,64 +2,78 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"io"
 	"os"
-	"strings"
+	"sync"
 
 	"github.com/4meepo/tagalign"
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/singlechecker"
 )
 
 func main() {
-	var noalign bool
-	var sort bool
-	var order string
-	var strict bool
-
-	// just for declaration.
-	flag.BoolVar(&noalign, "noalign", false, "Whether disable tags align. Default is false.")
-	flag.BoolVar(&sort, "sort", false, "Whether enable tags sort. Default is false.")
-	flag.BoolVar(&strict, "strict", false, "Whether enable strict style. Default is false. Note: strict must be used with align and sort together.")
-	flag.StringVar(&order, "order", "", "Specify the order of tags, the other tags will be sorted by name.")
-
-	// read from os.Args
-	args := os.Args
-	for i, arg := range args {
-		if arg == "-noalign" {
-			noalign = true
-		}
-		if arg == "-sort" {
-			sort = true
-		}
-		if arg == "-strict" {
-			strict = true
-		}
-		if arg == "-order" {
-			order = args[i+1]
-		}
+	if len(os.Args) > 1 && os.Args[1] == "stats" {
+		os.Exit(statsMain(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "infer" {
+		os.Exit(inferMain(os.Args[2:]))
 	}
 
-	var options []tagalign.Option
-	if noalign {
-		options = append(options, tagalign.WithAlign(false))
+	l := newLinter()
+	a := l.Analyzer()
+	if len(os.Args) > 1 && os.Args[1] == "fmt" {
+		os.Exit(formatMain(l, os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
 	}
-	if sort {
-		var orders []string
-		if order != "" {
-			orders = strings.Split(order, ",")
-		}
-		options = append(options, tagalign.WithSort(orders...))
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		os.Exit(checkMain(l, os.Args[2:], os.Stdout, os.Stderr))
 	}
-	if strict {
-		if noalign {
-			// cannot use noalign and strict together.
-			panic("cannot use `-noalign` and `-strict` together.")
+	d := newDriver(a)
+	if ok, err := d.parse(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	} else if ok {
+		if !validate(l, os.Stderr) {
+			os.Exit(2)
 		}
-		if !sort {
-			// cannot use strict without sort.
-			panic("cannot use `-strict` without `-sort`.")
-		}
-		options = append(options, tagalign.WithStrictStyle())
+		os.Exit(d.run())
 	}
 
-	singlechecker.Main(tagalign.NewAnalyzer(options...))
+	// the flags are parsed by singlechecker, so the options are validated when the analyzer first runs.
+	run := a.Run
+	var once sync.Once
+	a.Run = func(pass *analysis.Pass) (any, error) {
+		once.Do(func() {
+			if !validate(l, os.Stderr) {
+				os.Exit(2)
+			}
+		})
+		return run(pass)
+	}
+
+	// -format is handled by the driver, it's registered to be listed by -help.
+	flag.String("format", "", formatUsage)
+	singlechecker.Main(a)
+}
+
+// validate reports the invalid options of the linter once, before running it.
+// It reports whether the options are valid.
+func validate(l *tagalign.Linter, stderr io.Writer) bool {
+	if err := l.Validate(); err != nil {
+		fmt.Fprintln(stderr, "tagalign:", err)
+		return false
+	}
+
+	return true
+}
+
+// newLinter returns the linter of the command, which looks for configuration files by default.
+func newLinter(options ...tagalign.Option) *tagalign.Linter {
+	l := tagalign.NewLinter(options...)
+
+	config := l.Analyzer().Flags.Lookup("config")
+	config.DefValue = "true"
+	_ = config.Value.Set("true")
+
+	return l
 }
@@ -0,0 +1,6 @@
+package check
+
+//tagalign:style=loose
+type Directive struct {
+	ID int `json:"id"`
+}
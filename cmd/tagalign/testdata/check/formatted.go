@@ -0,0 +1,6 @@
+package check
+
+type Formatted struct {
+	Foo    int    `json:"foo"     yaml:"foo"`
+	FooBar string `json:"foo_bar" yaml:"foo_bar"`
+}
@@ -0,0 +1,5 @@
+package check
+
+type Gorm struct {
+	ID int `gorm:"primaryKey:yes"`
+}
@@ -0,0 +1,7 @@
+//go:build ignore
+
+package check
+
+type Ignored struct {
+	Foo int `json:"foo"`
+}
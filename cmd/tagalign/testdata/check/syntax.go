@@ -0,0 +1,5 @@
+package check
+
+type Syntax struct {
+	Foo int `json:"foo`
+}
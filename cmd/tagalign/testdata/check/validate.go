@@ -0,0 +1,5 @@
+package check
+
+type Validate struct {
+	Name string `validate:"requried"`
+}
@@ -0,0 +1,287 @@
+package tagalign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileNames are the names of configuration files, in the order of precedence within a directory.
+var ConfigFileNames = []string{".tagalign.yaml", ".tagalign.yml", ".tagalign.json"}
+
+// Config is the content of a configuration file.
+// Fields absent in a configuration file are inherited from the configuration files in parent directories.
+type Config struct {
+	// Root stops looking for configuration files in parent directories.
+	Root bool `json:"root" yaml:"root"`
+
+	Settings `yaml:",inline"`
+
+	// Overrides are applied in order to the files matching their paths,
+	// on top of the configuration of the file and its parent directories.
+	Overrides []Override `json:"overrides" yaml:"overrides"`
+
+	keys []string // the keys present in the configuration file.
+}
+
+// Override is a section of a configuration file which only applies to some files.
+type Override struct {
+	// Paths are glob patterns relative to the directory of the configuration file, separated by slashes.
+	// `**` matches any number of directories, and a pattern matching a directory matches all files in it.
+	Paths []string `json:"paths" yaml:"paths"`
+
+	Config `yaml:",inline"`
+}
+
+// DefaultConfig returns the configuration used when there is no configuration file.
+func DefaultConfig() Config {
+	return Config{Settings: DefaultSettings()}
+}
+
+// merge sets the settings of c present in src.
+func (c *Config) merge(src Config) {
+	dst, v := reflect.ValueOf(&c.Settings).Elem(), reflect.ValueOf(src.Settings)
+	for i := 0; i < v.NumField(); i++ {
+		key, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+		if slices.Contains(src.keys, key) {
+			dst.Field(i).Set(v.Field(i))
+		}
+	}
+}
+
+func (c *Config) UnmarshalYAML(node *yaml.Node) error {
+	if err := c.decodeYAML(node); err != nil {
+		return err
+	}
+
+	return c.checkKeys()
+}
+
+func (c *Config) UnmarshalJSON(data []byte) error {
+	if err := c.decodeJSON(data); err != nil {
+		return err
+	}
+
+	return c.checkKeys()
+}
+
+func (o *Override) UnmarshalYAML(node *yaml.Node) error {
+	if err := o.Config.decodeYAML(node); err != nil {
+		return err
+	}
+
+	return o.decodePaths(node.Decode)
+}
+
+func (o *Override) UnmarshalJSON(data []byte) error {
+	if err := o.Config.decodeJSON(data); err != nil {
+		return err
+	}
+
+	return o.decodePaths(func(v any) error { return json.Unmarshal(data, v) })
+}
+
+// decodeYAML decodes the node and records the keys present.
+func (c *Config) decodeYAML(node *yaml.Node) error {
+	type plain Config
+	if err := node.Decode((*plain)(c)); err != nil {
+		return err
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		c.keys = append(c.keys, node.Content[i].Value)
+	}
+
+	return nil
+}
+
+// decodeJSON decodes the data and records the keys present.
+func (c *Config) decodeJSON(data []byte) error {
+	type plain Config
+	if err := json.Unmarshal(data, (*plain)(c)); err != nil {
+		return err
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	for key := range m {
+		c.keys = append(c.keys, key)
+	}
+
+	return nil
+}
+
+func (o *Override) decodePaths(decode func(v any) error) error {
+	var v struct {
+		Paths []string `json:"paths" yaml:"paths"`
+	}
+	if err := decode(&v); err != nil {
+		return err
+	}
+	if len(v.Paths) == 0 {
+		return errors.New("override without paths")
+	}
+	o.Paths = v.Paths
+
+	o.keys = slices.DeleteFunc(o.keys, func(key string) bool { return key == "paths" })
+	for _, key := range o.keys {
+		if key == "root" || key == "overrides" {
+			return fmt.Errorf("field %q is not allowed in an override", key)
+		}
+	}
+
+	return o.checkKeys()
+}
+
+// checkKeys returns an error if a key present is not a field of the configuration,
+// since the decoders don't check it for custom unmarshalers.
+func (c *Config) checkKeys() error {
+	t := reflect.TypeOf(*c)
+	for _, key := range c.keys {
+		known := slices.ContainsFunc(reflect.VisibleFields(t), func(f reflect.StructField) bool {
+			name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+			return name == key
+		})
+		if !known {
+			return fmt.Errorf("unknown field %q", key)
+		}
+	}
+
+	return nil
+}
+
+// configFile is a configuration file found for a directory.
+type configFile struct {
+	dir    string // the directory of the configuration file.
+	config Config
+}
+
+// resolveConfig returns the configuration for the file, merged from the configuration files in order.
+func resolveConfig(files []configFile, filename string) Config {
+	cfg := DefaultConfig()
+	for _, f := range files {
+		cfg.merge(f.config)
+
+		rel, err := filepath.Rel(f.dir, filename)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		for _, o := range f.config.Overrides {
+			if slices.ContainsFunc(o.Paths, func(pattern string) bool { return matchGlob(pattern, rel) }) {
+				cfg.merge(o.Config)
+			}
+		}
+	}
+
+	return cfg
+}
+
+// LoadConfig looks for configuration files in dir and its parent directories, until a configuration file
+// with `root: true` or the root of the file system, and returns the options of the merged configuration.
+// Configuration files in nested directories override the ones in their parent directories,
+// and the overrides of a configuration file are resolved for each file by its name.
+// It returns no option if there is no configuration file.
+func LoadConfig(dir string) ([]Option, error) {
+	files, err := findConfigFiles(dir)
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+
+	var overridden bool
+	for _, f := range files {
+		overridden = overridden || len(f.config.Overrides) > 0
+	}
+	if !overridden {
+		return resolveConfig(files, dir).Options(), nil
+	}
+
+	return []Option{func(h *Helper) {
+		for _, opt := range resolveConfig(files, h.filename).Options() {
+			opt(h)
+		}
+	}}, nil
+}
+
+// findConfigFiles returns the configuration files which apply to dir, from the outermost to the innermost.
+func findConfigFiles(dir string) ([]configFile, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []configFile
+	for {
+		file, err := configFileIn(dir)
+		if err != nil {
+			return nil, err
+		}
+		if file != "" {
+			var cfg Config
+			if err := decodeConfigFile(file, &cfg); err != nil {
+				return nil, err
+			}
+			files = append(files, configFile{dir: dir, config: cfg})
+			if cfg.Root {
+				break
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	slices.Reverse(files)
+
+	return files, nil
+}
+
+func configFileIn(dir string) (string, error) {
+	for _, name := range ConfigFileNames {
+		file := filepath.Join(dir, name)
+		info, err := os.Stat(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if !info.IsDir() {
+			return file, nil
+		}
+	}
+
+	return "", nil
+}
+
+// decodeConfigFile decodes the configuration file into v.
+func decodeConfigFile(file string, v any) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	if filepath.Ext(file) == ".json" {
+		err = json.Unmarshal(data, v)
+	} else {
+		err = yaml.Unmarshal(data, v)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid configuration file %s: %w", file, err)
+	}
+
+	return nil
+}
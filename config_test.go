@@ -0,0 +1,152 @@
+package tagalign
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+	require.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+}
+
+func TestLoadConfig(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".tagalign.yaml"), "sort: true\norder: [json, yaml]\nduplicate: [json]\n")
+	writeFile(t, filepath.Join(root, "db", ".tagalign.json"), `{"order": ["gorm", "json"], "strict": true}`)
+	writeFile(t, filepath.Join(root, "api", "v1", ".tagalign.yaml"), "root: true\nalign: false\n")
+
+	t.Run("no config", func(t *testing.T) {
+		options, err := LoadConfig(filepath.Join(root, ".."))
+		require.NoError(t, err)
+		assert.Empty(t, options)
+	})
+
+	t.Run("single config", func(t *testing.T) {
+		options, err := LoadConfig(filepath.Join(root, "pkg"))
+		require.NoError(t, err)
+
+		h := newHelper("", options...)
+		assert.True(t, h.align)
+		assert.True(t, h.sort)
+		assert.Equal(t, []string{"json", "yaml"}, h.fixedTagOrder)
+		assert.Equal(t, []string{"json"}, h.duplicateKeys)
+		assert.Equal(t, DefaultStyle, h.style)
+	})
+
+	t.Run("merged with parent", func(t *testing.T) {
+		options, err := LoadConfig(filepath.Join(root, "db", "models"))
+		require.NoError(t, err)
+
+		h := newHelper("", options...)
+		assert.True(t, h.sort)
+		assert.Equal(t, []string{"gorm", "json"}, h.fixedTagOrder)
+		assert.Equal(t, []string{"json"}, h.duplicateKeys)
+		assert.Equal(t, StrictStyle, h.style)
+	})
+
+	t.Run("root config", func(t *testing.T) {
+		options, err := LoadConfig(filepath.Join(root, "api", "v1"))
+		require.NoError(t, err)
+
+		h := newHelper("", options...)
+		assert.False(t, h.align)
+		assert.False(t, h.sort)
+		assert.Empty(t, h.duplicateKeys)
+	})
+
+	t.Run("order without sort", func(t *testing.T) {
+		dir := filepath.Join(root, "nosort")
+		writeFile(t, filepath.Join(dir, ".tagalign.yaml"), "root: true\norder: [json]\n")
+
+		options, err := LoadConfig(dir)
+		require.NoError(t, err)
+		assert.ErrorContains(t, ValidateOptions(options...), "order requires sort")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		dir := filepath.Join(root, "invalid")
+		writeFile(t, filepath.Join(dir, ".tagalign.yaml"), "sorted: true\n")
+
+		_, err := LoadConfig(dir)
+		assert.ErrorContains(t, err, "invalid configuration file")
+	})
+}
+
+func TestLoadConfig_overrides(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".tagalign.yaml"), `
+sort: true
+order: [json, validate]
+overrides:
+  - paths: ["internal/db/**"]
+    order: [gorm, json]
+  - paths: ["**/*_gen.go"]
+    align: false
+`)
+	writeFile(t, filepath.Join(root, "internal", "db", "legacy", ".tagalign.json"), `{
+	"overrides": [{"paths": ["old.go"], "sort": false}]
+}`)
+
+	resolve := func(filename string) *Helper {
+		t.Helper()
+		options, err := LoadConfig(filepath.Dir(filename))
+		require.NoError(t, err)
+		return newHelper(filename, options...)
+	}
+
+	h := resolve(filepath.Join(root, "api", "user.go"))
+	assert.True(t, h.align)
+	assert.Equal(t, []string{"json", "validate"}, h.fixedTagOrder)
+
+	h = resolve(filepath.Join(root, "internal", "db", "user.go"))
+	assert.True(t, h.align)
+	assert.Equal(t, []string{"gorm", "json"}, h.fixedTagOrder)
+
+	h = resolve(filepath.Join(root, "internal", "db", "user_gen.go"))
+	assert.False(t, h.align)
+	assert.Equal(t, []string{"gorm", "json"}, h.fixedTagOrder)
+
+	h = resolve(filepath.Join(root, "internal", "db", "legacy", "old.go"))
+	assert.False(t, h.sort)
+
+	h = resolve(filepath.Join(root, "internal", "db", "legacy", "new.go"))
+	assert.True(t, h.sort)
+	assert.Equal(t, []string{"gorm", "json"}, h.fixedTagOrder)
+
+	t.Run("invalid override", func(t *testing.T) {
+		dir := filepath.Join(root, "invalid")
+		writeFile(t, filepath.Join(dir, ".tagalign.yaml"), "overrides:\n  - paths: [a.go]\n    sorted: true\n")
+		_, err := LoadConfig(dir)
+		assert.ErrorContains(t, err, `unknown field "sorted"`)
+
+		writeFile(t, filepath.Join(dir, ".tagalign.yaml"), "overrides:\n  - sort: true\n")
+		_, err = LoadConfig(dir)
+		assert.ErrorContains(t, err, "override without paths")
+	})
+}
+
+func Test_matchGlob(t *testing.T) {
+	assert.True(t, matchGlob("internal/db/**", "internal/db/user.go"))
+	assert.True(t, matchGlob("internal/db/**", "internal/db/models/user.go"))
+	assert.True(t, matchGlob("internal/db", "internal/db/user.go"))
+	assert.True(t, matchGlob("**/*_gen.go", "user_gen.go"))
+	assert.True(t, matchGlob("**/*_gen.go", "a/b/user_gen.go"))
+	assert.True(t, matchGlob("api/*/dto.go", "api/v1/dto.go"))
+	assert.False(t, matchGlob("api/*/dto.go", "api/v1/v2/dto.go"))
+	assert.False(t, matchGlob("internal/db/**", "internal/api/user.go"))
+	assert.False(t, matchGlob("*.go", "sub/user.go"))
+}
+
+func Test_matchFile(t *testing.T) {
+	assert.True(t, matchFile("*.pb.go", "/src/api/user.pb.go"))
+	assert.True(t, matchFile("internal/**", "/src/internal/db/user.go"))
+	assert.True(t, matchFile("/src/api/*.go", "/src/api/user.go"))
+	assert.False(t, matchFile("/api/*.go", "/src/api/user.go"))
+	assert.False(t, matchFile("*.pb.go", "/src/api/user.go"))
+}
@@ -0,0 +1,223 @@
+package tagalign
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const directivePrefix = "//tagalign:"
+
+// directive is a comment like `//tagalign:name=value`, the value is optional.
+type directive struct {
+	comment *ast.Comment
+	name    string
+	value   string
+}
+
+// parseDirectives returns the tagalign directives in the comment groups.
+func parseDirectives(groups ...*ast.CommentGroup) []directive {
+	var directives []directive
+	for _, cg := range groups {
+		if cg == nil {
+			continue
+		}
+		for _, c := range cg.List {
+			text, ok := strings.CutPrefix(c.Text, directivePrefix)
+			if !ok {
+				continue
+			}
+			// anything after a space is a free-form explanation.
+			text, _, _ = strings.Cut(text, " ")
+			name, value, _ := strings.Cut(text, "=")
+			directives = append(directives, directive{comment: c, name: name, value: value})
+		}
+	}
+
+	return directives
+}
+
+func hasIgnoreDirective(groups ...*ast.CommentGroup) bool {
+	for _, d := range parseDirectives(groups...) {
+		if d.name == "ignore" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ignored reports whether the node is skipped by a `//tagalign:ignore` directive,
+// either in the doc of a type declaration or in the doc or line comment of a struct field.
+func ignored(n ast.Node) bool {
+	switch v := n.(type) {
+	case *ast.GenDecl:
+		return v.Tok == token.TYPE && hasIgnoreDirective(v.Doc)
+	case *ast.TypeSpec:
+		return hasIgnoreDirective(v.Doc, v.Comment)
+	case *ast.Field:
+		return hasIgnoreDirective(v.Doc, v.Comment)
+	}
+
+	return false
+}
+
+// fileIgnored reports whether the file has a `//tagalign:ignore` directive before the package clause.
+func fileIgnored(f *ast.File) bool {
+	for _, cg := range f.Comments {
+		if cg.Pos() >= f.Package {
+			break
+		}
+		if hasIgnoreDirective(cg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// directiveError is an error of a directive found by the engine, reported by Process.
+type directiveError struct {
+	directive directive
+	msg       string
+}
+
+// structOverride is the settings of a struct overridden by directives on its type declaration.
+type structOverride struct {
+	order []string // present if overridden by `//tagalign:order=...`, it enables sort as well.
+	style *Style   // present if overridden by `//tagalign:style=...`.
+}
+
+var styleNames = map[string]Style{
+	"default": DefaultStyle,
+	"strict":  StrictStyle,
+}
+
+// checkDirectives reports malformed directives in the file.
+func (w *Helper) checkDirectives(pass *analysis.Pass, f *ast.File) {
+	for _, d := range parseDirectives(f.Comments...) {
+		if msg := w.validateDirective(d); msg != "" {
+			w.reportDirective(pass, d, msg)
+		}
+	}
+}
+
+// validateDirective returns the message of the error of the directive, the keys of an order are validated
+// the same way as the order of the options, see ValidateOptions.
+func (w *Helper) validateDirective(d directive) string {
+	switch d.name {
+	case "ignore":
+		if d.value != "" {
+			return "tagalign:ignore does not take a value"
+		}
+	case "order":
+		if d.value == "" {
+			return "tagalign:order requires a comma separated list of tag keys"
+		}
+		if err := errors.Join(w.validateOrder(strings.Split(d.value, ","))...); err != nil {
+			return "invalid tagalign:order: " + strings.ReplaceAll(err.Error(), "\n", "; ")
+		}
+	case "style":
+		if _, ok := styleNames[d.value]; !ok {
+			return fmt.Sprintf("tagalign:style has an unknown style %q, should be one of default and strict", d.value)
+		}
+	default:
+		return fmt.Sprintf("unknown tagalign directive %q", d.name)
+	}
+
+	return ""
+}
+
+func (w *Helper) reportDirective(pass *analysis.Pass, d directive, msg string) {
+	pass.Report(analysis.Diagnostic{
+		Pos:      d.comment.Pos(),
+		End:      d.comment.End(),
+		Category: RuleDirective,
+		Message:  msg,
+	})
+}
+
+// findOverrides records the settings overridden by the directives on the struct types of a type declaration.
+func (w *Helper) findOverrides(decl *ast.GenDecl) {
+	if decl.Tok != token.TYPE {
+		return
+	}
+
+	for _, spec := range decl.Specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		if !ok {
+			continue
+		}
+		st, ok := ts.Type.(*ast.StructType)
+		if !ok {
+			continue
+		}
+
+		groups := []*ast.CommentGroup{ts.Doc, ts.Comment}
+		if !decl.Lparen.IsValid() {
+			// the doc of `type Foo struct` is attached to the declaration.
+			groups = append(groups, decl.Doc)
+		}
+
+		var o structOverride
+		var found bool
+		for _, d := range parseDirectives(groups...) {
+			if w.validateDirective(d) != "" {
+				// reported by checkDirectives.
+				continue
+			}
+			switch d.name {
+			case "order":
+				o.order = strings.Split(d.value, ",")
+				found = true
+			case "style":
+				style := styleNames[d.value]
+				o.style = &style
+				found = true
+			}
+		}
+		if !found {
+			continue
+		}
+
+		if o.style != nil && *o.style == StrictStyle && (!w.align || (!w.sort && o.order == nil)) {
+			for _, d := range parseDirectives(groups...) {
+				if d.name == "style" {
+					w.directiveErrors = append(w.directiveErrors, directiveError{d,
+						"tagalign:style=strict requires align and sort enabled, or an order directive"})
+				}
+			}
+		}
+
+		if w.structOverrides == nil {
+			w.structOverrides = make(map[*ast.StructType]*structOverride)
+		}
+		w.structOverrides[st] = &o
+	}
+}
+
+// withOverride returns the helper with the settings overridden by the struct which the field belongs to.
+func (w *Helper) withOverride(field *ast.Field) *Helper {
+	o, ok := w.fieldOverrides[field]
+	if !ok {
+		return w
+	}
+
+	h := *w
+	if o.order != nil {
+		h.sort = true
+		h.fixedTagOrder = o.order
+	}
+	if o.style != nil {
+		h.style = *o.style
+	}
+	if h.style == StrictStyle && (!h.align || !h.sort) {
+		h.style = DefaultStyle
+	}
+
+	return &h
+}
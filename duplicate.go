@@ -0,0 +1,293 @@
+package tagalign
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// serializedField is a field visible to the encoder, collected the same way encoding/json does.
+type serializedField struct {
+	name   string // serialized name.
+	tagged bool   // whether the name comes from the tag.
+	index  []int  // index path from the checked struct.
+	path   string // field names along the index path, e.g. "Base.ID".
+}
+
+// checkDuplicateNames reports fields which are serialized to the same name by the configured keys.
+// It follows the visibility and depth rules of encoding/json, so fields promoted from embedded structs are included,
+// except for the keys whose encoders fail on any duplicate name, see failingKeys.
+func (w *Helper) checkDuplicateNames(pass *analysis.Pass, n ast.Node) {
+	if len(w.duplicateKeys) == 0 || pass.TypesInfo == nil {
+		return
+	}
+
+	v, ok := n.(*ast.StructType)
+	if !ok {
+		return
+	}
+
+	st, ok := pass.TypesInfo.TypeOf(v).(*types.Struct)
+	if !ok {
+		return
+	}
+
+	// map the index of types.Struct fields to the ast fields.
+	var astFields []*ast.Field
+	for _, field := range v.Fields.List {
+		if len(field.Names) == 0 {
+			astFields = append(astFields, field)
+			continue
+		}
+		for range field.Names {
+			astFields = append(astFields, field)
+		}
+	}
+	if len(astFields) != st.NumFields() {
+		return
+	}
+
+	for _, key := range w.duplicateKeys {
+		fields := serializedFields(st, key)
+		for i := 0; i < len(fields); {
+			j := i + 1
+			for j < len(fields) && fields[j].name == fields[i].name {
+				j++
+			}
+			w.reportDuplicates(pass, key, fields[i:j], astFields)
+			i = j
+		}
+	}
+}
+
+func (w *Helper) reportDuplicates(pass *analysis.Pass, key string, fields []serializedField, astFields []*ast.Field) {
+	if len(fields) < 2 {
+		return
+	}
+
+	// a conflict inside an embedded struct is reported where the embedded struct is declared.
+	if !slices.ContainsFunc(fields, func(f serializedField) bool { return f.index[0] != fields[0].index[0] }) {
+		return
+	}
+
+	if slices.Contains(failingKeys, key) {
+		// the encoder fails on any duplicate name, none of the fields dominates.
+		w.reportConflicts(pass, fields, astFields, fmt.Sprintf("%s name %q is duplicated by %s, encoding fails",
+			key, fields[0].name, joinPaths(fields)))
+		return
+	}
+
+	dominant := fields[0]
+	if len(dominant.index) == len(fields[1].index) && dominant.tagged == fields[1].tagged {
+		// no field dominates, encoding/json ignores all of them.
+		var conflicts []serializedField
+		for _, f := range fields {
+			if len(f.index) == len(dominant.index) && f.tagged == dominant.tagged {
+				conflicts = append(conflicts, f)
+			}
+		}
+		w.reportConflicts(pass, conflicts, astFields, fmt.Sprintf(
+			"%s name %q is used by %s at the same depth, all of them are ignored", key, dominant.name, joinPaths(conflicts)))
+		return
+	}
+
+	var shadowed []string
+	for _, f := range fields[1:] {
+		if f.index[0] != dominant.index[0] {
+			shadowed = append(shadowed, f.path)
+		}
+	}
+	if len(shadowed) == 0 {
+		return
+	}
+
+	field := astFields[dominant.index[0]]
+	if ignored(field) {
+		return
+	}
+	pass.Report(analysis.Diagnostic{
+		Pos:      field.Pos(),
+		End:      field.End(),
+		Category: RuleDuplicate,
+		Message:  fmt.Sprintf("%s name %q of %s shadows %s", key, dominant.name, dominant.path, strings.Join(shadowed, ", ")),
+	})
+}
+
+// reportConflicts reports the message once on each field of the struct which the conflicting fields belong to.
+func (w *Helper) reportConflicts(pass *analysis.Pass, conflicts []serializedField, astFields []*ast.Field, msg string) {
+	reported := make(map[int]bool)
+	for _, f := range conflicts {
+		if reported[f.index[0]] {
+			continue
+		}
+		reported[f.index[0]] = true
+
+		field := astFields[f.index[0]]
+		if ignored(field) {
+			continue
+		}
+		pass.Report(analysis.Diagnostic{
+			Pos:      field.Pos(),
+			End:      field.End(),
+			Category: RuleDuplicate,
+			Message:  msg,
+		})
+	}
+}
+
+// joinPaths returns the paths of the fields separated by commas.
+func joinPaths(fields []serializedField) string {
+	paths := make([]string, len(fields))
+	for i, f := range fields {
+		paths[i] = f.path
+	}
+
+	return strings.Join(paths, ", ")
+}
+
+// failingKeys are the tag keys whose encoders fail on duplicate names, e.g. gopkg.in/yaml.v3 and the mongo bson codec,
+// instead of picking the dominant field like encoding/json.
+var failingKeys = []string{"yaml", "bson"}
+
+// inlineOptions maps tag keys to the option inlining an embedded struct, for the encoders which don't inline them by default.
+var inlineOptions = map[string]string{
+	"yaml":         "inline",
+	"bson":         "inline",
+	"mapstructure": "squash",
+}
+
+// serializedFields returns the fields of st visible to the encoder, sorted by name, depth and whether tagged,
+// the same as typeFields in encoding/json. The default names of the key, see defaultNames, are used for untagged fields,
+// and embedded structs are only inlined with the inline option of the key, see inlineOptions.
+func serializedFields(st *types.Struct, key string) []serializedField {
+	type embedded struct {
+		typ   *types.Struct
+		index []int
+		path  string
+	}
+
+	var fields []serializedField
+	current, next := []embedded{}, []embedded{{typ: st}}
+	var count, nextCount map[*types.Struct][]embedded
+	visited := make(map[*types.Struct]bool)
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, make(map[*types.Struct][]embedded)
+
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := 0; i < e.typ.NumFields(); i++ {
+				sf := e.typ.Field(i)
+				ft := sf.Type()
+				if p, ok := types.Unalias(ft).(*types.Pointer); ok {
+					ft = p.Elem()
+				}
+				embeddedStruct, isStruct := ft.Underlying().(*types.Struct)
+
+				if sf.Embedded() {
+					if !sf.Exported() && !isStruct {
+						continue
+					}
+				} else if !sf.Exported() {
+					continue
+				}
+
+				tag := reflect.StructTag(e.typ.Tag(i)).Get(key)
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				if !isValidSerializedName(name) {
+					name = ""
+				}
+				inline := true
+				if opt, ok := inlineOptions[key]; ok {
+					inline = slices.Contains(strings.Split(opts, ","), opt)
+				}
+
+				index := append(slices.Clone(e.index), i)
+				path := sf.Name()
+				if e.path != "" {
+					path = e.path + "." + path
+				}
+
+				if name != "" || !sf.Embedded() || !isStruct || !inline {
+					if sf.Embedded() && !sf.Exported() {
+						// an unexported embedded struct which isn't inlined.
+						continue
+					}
+					tagged := name != ""
+					if name == "" {
+						name = sf.Name()
+						if defaultName, ok := defaultNames[key]; ok {
+							name = defaultName(name)
+						}
+					}
+					fields = append(fields, serializedField{name: name, tagged: tagged, index: index, path: path})
+					if others := count[e.typ]; len(others) > 1 {
+						// the embedded struct appears more than once at this depth,
+						// add a second one so that the fields annihilate each other.
+						other := others[1]
+						fields = append(fields, serializedField{
+							name:   name,
+							tagged: tagged,
+							index:  append(slices.Clone(other.index), i),
+							path:   other.path + "." + sf.Name(),
+						})
+					}
+					continue
+				}
+
+				emb := embedded{typ: embeddedStruct, index: index, path: path}
+				nextCount[embeddedStruct] = append(nextCount[embeddedStruct], emb)
+				if len(nextCount[embeddedStruct]) == 1 {
+					next = append(next, emb)
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(fields, func(a, b serializedField) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if len(a.index) != len(b.index) {
+			return len(a.index) - len(b.index)
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return slices.Compare(a.index, b.index)
+	})
+
+	return fields
+}
+
+// isValidSerializedName reports whether name is accepted by encoding/json as a field name.
+func isValidSerializedName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
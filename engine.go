@@ -0,0 +1,362 @@
+package tagalign
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alfatraining/structtag"
+)
+
+// Group is a group of consecutive tagged fields of a struct, whose tags are aligned together.
+// A tagged field which isn't next to another one is a group on its own.
+type Group struct {
+	Fields  []*ast.Field
+	Columns []Column   // the layout of the aligned tags, empty if align is disabled or for a single field.
+	Tags    []FieldTag // the tags proposed for the fields which are not formatted.
+}
+
+// Column is a column of aligned tags.
+type Column struct {
+	Key   string // the key of the tags in the column in strict style, empty otherwise.
+	Width int    // the width of the widest tag in the column.
+}
+
+// FieldTag is the tag proposed for a field.
+type FieldTag struct {
+	Field   *ast.Field
+	Value   string // the proposed tag literal with back quotes, the original one if Err is not nil.
+	Message string
+	Rule    string // RuleAlignment, RuleOrder or RuleSyntax.
+	Err     error  // the error parsing the original tag.
+}
+
+// The rules of the tags proposed by the engine, used as the category of their diagnostics.
+const (
+	RuleAlignment = "alignment" // the tags are not aligned or formatted.
+	RuleOrder     = "order"     // the tags are not sorted.
+	RuleSyntax    = "syntax"    // the tag is invalid.
+)
+
+// The rules of the checks, used as the category of their diagnostics.
+const (
+	RuleGorm      = "gorm"      // the gorm tag has an invalid value, see WithGormCheck.
+	RuleValidate  = "validate"  // the validate or binding tag has an invalid value, see WithValidateCheck.
+	RuleKeys      = "keys"      // the tag key is not allowed or required.
+	RuleDuplicate = "duplicate" // the serialized name is duplicated, see WithDuplicateCheck.
+	RuleRedundant = "redundant" // the tag is equal to the default behavior, see WithRedundantCheck.
+	RuleDirective = "directive" // the tagalign directive is malformed.
+)
+
+// Edit returns the edit replacing the tag of the field by the proposed one,
+// the tag literal is removed if all tags are removed.
+func (t FieldTag) Edit() Edit {
+	if t.Value == "``" {
+		return Edit{Pos: t.Field.Type.End(), End: t.Field.Tag.End(), Message: t.Message}
+	}
+
+	return Edit{Pos: t.Field.Tag.Pos(), End: t.Field.Tag.End(), NewText: []byte(t.Value), Message: t.Message}
+}
+
+// Find records the groups of consecutive tagged fields of the struct node,
+// and the settings overridden by the directives of a type declaration node.
+// It's meant to be called by ast.Inspect on a file, before Groups.
+func (w *Helper) Find(fset *token.FileSet, n ast.Node) {
+	switch v := n.(type) {
+	case *ast.GenDecl:
+		w.findOverrides(v)
+		return
+	case *ast.TypeSpec:
+		w.findTypeNames(v)
+		return
+	}
+
+	v, ok := n.(*ast.StructType)
+	if !ok || !w.typeMatched(v) {
+		return
+	}
+	w.structs++
+
+	fields := v.Fields.List
+	if len(fields) == 0 {
+		return
+	}
+
+	if o, ok := w.structOverrides[v]; ok {
+		if w.fieldOverrides == nil {
+			w.fieldOverrides = make(map[*ast.Field]*structOverride)
+		}
+		for _, field := range fields {
+			w.fieldOverrides[field] = o
+		}
+	}
+
+	fs := make([]*ast.Field, 0)
+	split := func() {
+		n := len(fs)
+		if n > 1 {
+			w.consecutiveFieldsGroups = append(w.consecutiveFieldsGroups, fs)
+		} else if n == 1 {
+			w.singleFields = append(w.singleFields, fs[0])
+		}
+
+		fs = nil
+	}
+
+	for i, field := range fields {
+		if field.Tag == nil || ignored(field) {
+			// field without tags or ignored by directive
+			split()
+			continue
+		}
+
+		if i > 0 {
+			if fields[i-1].Tag == nil || ignored(fields[i-1]) {
+				// if previous filed do not have a tag
+				fs = append(fs, field)
+				continue
+			}
+			preLineNum := fset.Position(fields[i-1].Tag.Pos()).Line
+			lineNum := fset.Position(field.Tag.Pos()).Line
+			if lineNum-preLineNum > 1 {
+				// fields with tags are not consecutive, including two case:
+				// 1. splited by lines
+				// 2. splited by a struct
+				split()
+
+				// check if the field is a struct
+				if _, ok := field.Type.(*ast.StructType); ok {
+					continue
+				}
+			}
+		}
+
+		fs = append(fs, field)
+	}
+
+	split()
+}
+
+// Groups returns the groups found with their layouts and the tags proposed for them,
+// groups of several fields first in order, then single fields.
+func (w *Helper) Groups() []Group {
+	groups := make([]Group, 0, len(w.consecutiveFieldsGroups)+len(w.singleFields))
+	for _, fields := range w.consecutiveFieldsGroups {
+		groups = append(groups, w.withOverride(fields[0]).alignGroup(fields))
+	}
+	for _, field := range w.singleFields {
+		groups = append(groups, w.withOverride(field).formatField(field))
+	}
+
+	return groups
+}
+
+// parseTag parses the tag of the field, it returns the tag proposed with the error if it's invalid.
+func parseTag(field *ast.Field) (*structtag.Tags, *FieldTag) {
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		// if tag value is not a valid string, report it directly
+		return nil, &FieldTag{Field: field, Value: field.Tag.Value, Message: errTagValueSyntax, Rule: RuleSyntax, Err: err}
+	}
+
+	tags, err := structtag.Parse(tag)
+	if err != nil {
+		// if tag value is not a valid struct tag, report it directly
+		return nil, &FieldTag{Field: field, Value: field.Tag.Value, Message: err.Error(), Rule: RuleSyntax, Err: err}
+	}
+
+	return tags, nil
+}
+
+//nolint:gocognit,gocyclo,nestif
+func (w *Helper) alignGroup(fields []*ast.Field) Group {
+	var group Group
+
+	var maxTagNum int
+	var tagsGroup, notSortedTagsGroup, unalignedGroup [][]*structtag.Tag
+	var rewritten []bool
+
+	var uniqueKeys []string
+	addKey := func(k string) {
+		for _, key := range uniqueKeys {
+			if key == k {
+				return
+			}
+		}
+		uniqueKeys = append(uniqueKeys, k)
+	}
+
+	for _, field := range fields {
+		tags, invalid := parseTag(field)
+		if invalid != nil {
+			group.Tags = append(group.Tags, *invalid)
+			continue
+		}
+		group.Fields = append(group.Fields, field)
+
+		rewritten = append(rewritten, w.rewriteTags(field, tags))
+
+		cp := make([]*structtag.Tag, tags.Len())
+		for i, tag := range tags.Tags() {
+			cp[i] = tag
+		}
+		notSortedTagsGroup = append(notSortedTagsGroup, cp)
+		if w.sort {
+			sortTags(w.fixedTagOrder, tags)
+		}
+		aligned, unaligned := w.splitUnaligned(tags.Tags())
+		maxTagNum = max(maxTagNum, len(aligned))
+		for _, t := range aligned {
+			addKey(t.Key)
+		}
+		tagsGroup = append(tagsGroup, aligned)
+		unalignedGroup = append(unalignedGroup, unaligned)
+	}
+
+	if w.sort && StrictStyle == w.style {
+		sortKeys(w.fixedTagOrder, uniqueKeys)
+		maxTagNum = len(uniqueKeys)
+	}
+
+	// record the max length of each column tag
+	columns := make([]Column, maxTagNum)
+	for j := 0; j < maxTagNum; j++ {
+		var maxLength int
+		var key string
+		for i := 0; i < len(tagsGroup); i++ {
+			if w.style == StrictStyle {
+				key = uniqueKeys[j]
+				// search by key
+				for _, tag := range tagsGroup[i] {
+					if tag.Key == key {
+						maxLength = max(maxLength, len(tag.String()))
+						break
+					}
+				}
+			} else {
+				if len(tagsGroup[i]) <= j {
+					// in case of index out of range
+					continue
+				}
+				maxLength = max(maxLength, len(tagsGroup[i][j].String()))
+			}
+		}
+		columns[j] = Column{key, maxLength}
+	}
+	if w.align {
+		group.Columns = columns
+	}
+
+	for i, field := range group.Fields {
+		tags := tagsGroup[i]
+
+		var newTagStr string
+		if w.align {
+			// if align enabled, align tags.
+			newTagBuilder := strings.Builder{}
+			for i, n := 0, 0; i < len(tags) && n < len(columns); {
+				tag := tags[i]
+				var format string
+				if w.style == StrictStyle {
+					if columns[n].Key == tag.Key {
+						// match
+						format = alignFormat(columns[n].Width + 1) // with an extra space
+						newTagBuilder.WriteString(fmt.Sprintf(format, tag.String()))
+						i++
+						n++
+					} else {
+						// tag missing
+						format = alignFormat(columns[n].Width + 1)
+						newTagBuilder.WriteString(fmt.Sprintf(format, ""))
+						n++
+					}
+				} else {
+					format = alignFormat(columns[n].Width + 1) // with an extra space
+					newTagBuilder.WriteString(fmt.Sprintf(format, tag.String()))
+					i++
+					n++
+				}
+			}
+			newTagStr = joinTags(strings.TrimRight(newTagBuilder.String(), " "), unalignedGroup[i])
+		} else {
+			tags = slices.Concat(tags, unalignedGroup[i])
+			// otherwise check if tags order or value changed
+			if reflect.DeepEqual(notSortedTagsGroup[i], tags) && !rewritten[i] {
+				// if tags not changed, do nothing
+				continue
+			}
+			tagsStr := make([]string, len(tags))
+			for i, tag := range tags {
+				tagsStr[i] = tag.String()
+			}
+			newTagStr = strings.Join(tagsStr, " ")
+		}
+
+		unquoteTag := strings.TrimRight(newTagStr, " ")
+		newTagValue := fmt.Sprintf("`%s`", unquoteTag)
+		if field.Tag.Value == newTagValue {
+			// nothing changed
+			continue
+		}
+
+		msg := "tag is not aligned, should be: " + unquoteTag
+
+		rule := tagRule(notSortedTagsGroup[i], slices.Concat(tagsGroup[i], unalignedGroup[i]))
+		group.Tags = append(group.Tags, FieldTag{Field: field, Value: newTagValue, Message: msg, Rule: rule})
+	}
+
+	return group
+}
+
+func (w *Helper) formatField(field *ast.Field) Group {
+	group := Group{Fields: []*ast.Field{field}}
+
+	tags, invalid := parseTag(field)
+	if invalid != nil {
+		group.Tags = append(group.Tags, *invalid)
+		return group
+	}
+
+	w.rewriteTags(field, tags)
+	originalTags := append([]*structtag.Tag(nil), tags.Tags()...)
+	if w.sort {
+		sortTags(w.fixedTagOrder, tags)
+	}
+	aligned, unaligned := w.splitUnaligned(tags.Tags())
+	newTags := slices.Concat(aligned, unaligned)
+	newTagStr := joinTags("", newTags)
+
+	newTagValue := fmt.Sprintf("`%s`", newTagStr)
+	if reflect.DeepEqual(originalTags, newTags) && field.Tag.Value == newTagValue {
+		// if tags order not changed, do nothing
+		return group
+	}
+
+	msg := "tag is not aligned , should be: " + newTagStr
+
+	group.Tags = append(group.Tags, FieldTag{Field: field, Value: newTagValue, Message: msg, Rule: tagRule(originalTags, newTags)})
+
+	return group
+}
+
+// tagRule returns RuleOrder if the keys of the tags are reordered, RuleAlignment otherwise.
+// Removed tags, e.g. redundant ones, are not considered.
+func tagRule(original, tags []*structtag.Tag) string {
+	var keys []string
+	for _, tag := range original {
+		if slices.ContainsFunc(tags, func(t *structtag.Tag) bool { return t.Key == tag.Key }) {
+			keys = append(keys, tag.Key)
+		}
+	}
+	for i, tag := range tags {
+		if i < len(keys) && keys[i] != tag.Key {
+			return RuleOrder
+		}
+	}
+
+	return RuleAlignment
+}
@@ -0,0 +1,95 @@
+package tagalign
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/alfatraining/structtag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findGroups(t *testing.T, src string, options ...Option) []Group {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "example.go", src, parser.ParseComments)
+	require.NoError(t, err)
+
+	h, err := NewHelper(options...)
+	require.NoError(t, err)
+	ast.Inspect(file, func(n ast.Node) bool {
+		h.Find(fset, n)
+		return true
+	})
+
+	return h.Groups()
+}
+
+func TestHelper_Groups(t *testing.T) {
+	src := `package example
+
+type Example struct {
+	Foo    int    ` + "`json:\"foo\" yaml:\"foo\"`" + `
+	FooBar string ` + "`json:\"foo_bar\" xml:\"fb\"`" + `
+
+	Bar int ` + "`yaml:\"bar\" json:\"bar\"`" + `
+	Baz int ` + "`json:\"baz`" + `
+}
+`
+
+	t.Run("default style", func(t *testing.T) {
+		groups := findGroups(t, src)
+		require.Len(t, groups, 2)
+
+		g := groups[0]
+		assert.Len(t, g.Fields, 2)
+		assert.Equal(t, []Column{{Width: 14}, {Width: 10}}, g.Columns)
+		require.Len(t, g.Tags, 1)
+		assert.Equal(t, "`json:\"foo\"     yaml:\"foo\"`", g.Tags[0].Value)
+		assert.Equal(t, RuleAlignment, g.Tags[0].Rule)
+		assert.NoError(t, g.Tags[0].Err)
+
+		// the field with an invalid tag isn't aligned with the others.
+		g = groups[1]
+		assert.Len(t, g.Fields, 1)
+		require.Len(t, g.Tags, 1)
+		assert.Equal(t, errTagValueSyntax, g.Tags[0].Message)
+		assert.Equal(t, RuleSyntax, g.Tags[0].Rule)
+		assert.Error(t, g.Tags[0].Err)
+	})
+
+	t.Run("strict style", func(t *testing.T) {
+		groups := findGroups(t, src, WithSort("json"), WithStrictStyle())
+		require.Len(t, groups, 2)
+
+		g := groups[0]
+		assert.Equal(t, []Column{{Key: "json", Width: 14}, {Key: "xml", Width: 8}, {Key: "yaml", Width: 10}}, g.Columns)
+		require.Len(t, g.Tags, 1)
+		assert.Equal(t, "`json:\"foo\"              yaml:\"foo\"`", g.Tags[0].Value)
+	})
+
+	t.Run("no align", func(t *testing.T) {
+		groups := findGroups(t, src, WithAlign(false), WithSort())
+		require.Len(t, groups, 2)
+		assert.Empty(t, groups[0].Columns)
+		assert.Empty(t, groups[0].Tags)
+	})
+
+	_, err := NewHelper(WithStrictStyle())
+	assert.ErrorContains(t, err, "strict style requires align and sort")
+}
+
+func Test_tagRule(t *testing.T) {
+	parse := func(tag string) []*structtag.Tag {
+		tags, err := structtag.Parse(tag)
+		require.NoError(t, err)
+		return tags.Tags()
+	}
+
+	assert.Equal(t, RuleAlignment, tagRule(parse(`json:"a" yaml:"a"`), parse(`json:"a" yaml:"a"`)))
+	assert.Equal(t, RuleOrder, tagRule(parse(`yaml:"a" json:"a"`), parse(`json:"a" yaml:"a"`)))
+	assert.Equal(t, RuleAlignment, tagRule(parse(`json:"A" yaml:"a"`), parse(`yaml:"a"`)))
+}
@@ -0,0 +1,64 @@
+package tagalign
+
+import (
+	"go/ast"
+	"regexp"
+	"slices"
+	"sync"
+)
+
+// regexps caches the compiled patterns of the type and package filters, which are checked by validate.
+var regexps sync.Map
+
+func matchRegexp(pattern, s string) bool {
+	if re, ok := regexps.Load(pattern); ok {
+		return re.(*regexp.Regexp).MatchString(s)
+	}
+
+	re := regexp.MustCompile(pattern)
+	regexps.Store(pattern, re)
+
+	return re.MatchString(s)
+}
+
+// matchFilter reports whether s matches any include pattern, or any if there is none, and no exclude pattern.
+func matchFilter(include, exclude []string, s string) bool {
+	match := func(pattern string) bool { return matchRegexp(pattern, s) }
+	if len(include) > 0 && !slices.ContainsFunc(include, match) {
+		return false
+	}
+
+	return !slices.ContainsFunc(exclude, match)
+}
+
+// packageMatched reports whether the structs of the package are aligned by the package filters.
+func (w *Helper) packageMatched(path string) bool {
+	return matchFilter(w.includePackages, w.excludePackages, path)
+}
+
+// findTypeNames records the name of the type declaration for the structs in it, including the nested ones.
+func (w *Helper) findTypeNames(spec *ast.TypeSpec) {
+	if len(w.includeTypes) == 0 && len(w.excludeTypes) == 0 {
+		return
+	}
+
+	ast.Inspect(spec.Type, func(n ast.Node) bool {
+		if st, ok := n.(*ast.StructType); ok {
+			if w.typeNames == nil {
+				w.typeNames = make(map[*ast.StructType]string)
+			}
+			w.typeNames[st] = spec.Name.Name
+		}
+		return true
+	})
+}
+
+// typeMatched reports whether the struct is aligned by the type filters.
+// Structs outside of a type declaration have an empty name.
+func (w *Helper) typeMatched(st *ast.StructType) bool {
+	if len(w.includeTypes) == 0 && len(w.excludeTypes) == 0 {
+		return true
+	}
+
+	return matchFilter(w.includeTypes, w.excludeTypes, w.typeNames[st])
+}
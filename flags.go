@@ -0,0 +1,266 @@
+package tagalign
+
+import (
+	"flag"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// flags are the settings of the analyzer exposed by analysis.Analyzer.Flags,
+// so that every driver configures the analyzer the same way.
+// Only the flags set on the command line are applied, on top of the options of NewAnalyzer.
+type flags struct {
+	align         boolFlag
+	noalign       boolFlag
+	sort          boolFlag
+	order         listFlag
+	strict        boolFlag
+	unalignedKeys listFlag
+	duplicate     listFlag
+	gorm          boolFlag
+	gormCanonical boolFlag
+	validate      boolFlag
+	validateTags  listFlag
+	allowedKeys   listFlag
+	deniedKeys    listFlag
+	redundant     listFlag
+	generated     boolFlag
+	includeFiles  listFlag
+	excludeFiles  listFlag
+	includeTypes  listFlag
+	excludeTypes  listFlag
+	includePkgs   listFlag
+	excludePkgs   listFlag
+	config        boolFlag
+	newFromRev    stringFlag
+	newFromPatch  stringFlag
+
+	configs sync.Map // options loaded from configuration files by directory.
+
+	changesOnce sync.Once
+	changes     changedLines // the lines changed by -new-from-rev or -new-from-patch.
+	changesErr  error
+}
+
+func (f *flags) register(fs *flag.FlagSet) {
+	f.align.value = true
+
+	fs.Var(&f.align, "align", "Whether enable tags align. Default is true.")
+	fs.Var(&f.noalign, "noalign", "Whether disable tags align, deprecated alias for -align=false.")
+	fs.Var(&f.sort, "sort", "Whether enable tags sort. Default is false.")
+	fs.Var(&f.order, "order", "Specify the order of tags, the other tags will be sorted by name. It requires -sort.")
+	fs.Var(&f.strict, "strict", "Whether enable strict style. Default is false. Note: strict must be used with align and sort together.")
+	fs.Var(&f.unalignedKeys, "unaligned-keys", "Specify the tag keys excluded from alignment and placed at the end of the tags, e.g. \"validate,swaggertype\".")
+	fs.Var(&f.duplicate, "duplicate", "Specify the tag keys checked for duplicate serialized names, e.g. \"json,yaml\".")
+	fs.Var(&f.gorm, "gorm", "Whether enable gorm tags validation. Default is false.")
+	fs.Var(&f.gormCanonical, "gorm-canonical", "Whether rewrite gorm tags in canonical order and spelling. Default is false.")
+	fs.Var(&f.validate, "validate", "Whether enable validate and binding tags validation. Default is false.")
+	fs.Var(&f.validateTags, "validate-tags", "Specify the custom tags registered to the validator, e.g. \"is_code,is_name\".")
+	fs.Var(&f.allowedKeys, "allowed-keys", "Specify the tag keys allowed, e.g. \"json,yaml\". Any key is allowed by default.")
+	fs.Var(&f.deniedKeys, "denied-keys", "Specify the tag keys denied, optionally with the message reported, e.g. \"bson=mongo is not used,toml\".")
+	fs.Var(&f.redundant, "redundant", "Specify the tag keys checked for tags equal to the default behavior, e.g. \"json,yaml\".")
+	fs.Var(&f.generated, "generated", "Whether check generated files. Default is false.")
+	fs.Var(&f.includeFiles, "include-files", "Specify the glob patterns of the files checked, e.g. \"internal/**\". All files are checked by default.")
+	fs.Var(&f.excludeFiles, "exclude-files", "Specify the glob patterns of the files skipped, e.g. \"*.pb.go,*_gen.go\".")
+	fs.Var(&f.includeTypes, "include-types", "Specify the regular expressions of the type names aligned, e.g. \".*(Request|Response)$\". All types are aligned by default.")
+	fs.Var(&f.excludeTypes, "exclude-types", "Specify the regular expressions of the type names not aligned.")
+	fs.Var(&f.includePkgs, "include-packages", "Specify the regular expressions of the package paths aligned. All packages are aligned by default.")
+	fs.Var(&f.excludePkgs, "exclude-packages", "Specify the regular expressions of the package paths not aligned.")
+	fs.Var(&f.newFromRev, "new-from-rev", "Report only the groups of fields touching the lines changed since the git revision, e.g. \"main\".")
+	fs.Var(&f.newFromPatch, "new-from-patch", "Report only the groups of fields touching the lines added by the unified diff file, its file names are relative to the working directory.")
+	fs.Var(&f.config, "config", "Whether look for .tagalign.yaml configuration files in the directory of each package and its parents. Flags take precedence over configuration files.")
+}
+
+// options returns the options of the flags set on the command line.
+func (f *flags) options() []Option {
+	var options []Option
+	if f.align.set {
+		options = append(options, WithAlign(f.align.value))
+	}
+	if f.noalign.set && f.noalign.value {
+		options = append(options, WithAlign(false))
+	}
+	if f.sort.set {
+		options = append(options, func(h *Helper) { h.sort = f.sort.value })
+	}
+	if f.order.set {
+		options = append(options, func(h *Helper) { h.fixedTagOrder = f.order.values })
+	}
+	if f.strict.set {
+		if f.strict.value {
+			options = append(options, WithStrictStyle())
+		} else {
+			options = append(options, func(h *Helper) { h.style = DefaultStyle })
+		}
+	}
+	if f.unalignedKeys.set {
+		options = append(options, WithUnalignedKeys(f.unalignedKeys.values...))
+	}
+	if f.duplicate.set {
+		options = append(options, WithDuplicateNameCheck(f.duplicate.values...))
+	}
+	if f.gorm.set || f.gormCanonical.set {
+		options = append(options, func(h *Helper) {
+			if f.gorm.set {
+				h.gormCheck = f.gorm.value
+			}
+			if f.gormCanonical.set {
+				h.gormCanonical = f.gormCanonical.value
+				h.gormCheck = h.gormCheck || f.gormCanonical.value
+			}
+		})
+	}
+	if f.validate.set {
+		options = append(options, func(h *Helper) { h.validateCheck = f.validate.value })
+	}
+	if f.validateTags.set {
+		options = append(options, func(h *Helper) { h.validateCustomTags = f.validateTags.values })
+	}
+	if f.allowedKeys.set {
+		options = append(options, WithAllowedKeys(f.allowedKeys.values...))
+	}
+	if f.deniedKeys.set {
+		denied := make(map[string]string)
+		for _, entry := range f.deniedKeys.values {
+			key, msg, _ := strings.Cut(entry, "=")
+			denied[key] = msg
+		}
+		options = append(options, WithDeniedKeys(denied))
+	}
+	if f.redundant.set {
+		options = append(options, WithRedundantCheck(f.redundant.values...))
+	}
+	if f.generated.set {
+		options = append(options, WithGeneratedFiles(f.generated.value))
+	}
+	if f.includeFiles.set {
+		options = append(options, WithIncludeFiles(f.includeFiles.values...))
+	}
+	if f.excludeFiles.set {
+		options = append(options, WithExcludeFiles(f.excludeFiles.values...))
+	}
+	if f.includeTypes.set {
+		options = append(options, WithIncludeTypes(f.includeTypes.values...))
+	}
+	if f.excludeTypes.set {
+		options = append(options, WithExcludeTypes(f.excludeTypes.values...))
+	}
+	if f.includePkgs.set {
+		options = append(options, WithIncludePackages(f.includePkgs.values...))
+	}
+	if f.excludePkgs.set {
+		options = append(options, WithExcludePackages(f.excludePkgs.values...))
+	}
+
+	return options
+}
+
+// resolve returns the options for the files of the directory, they are applied in order:
+// options of NewLinter, configuration files, flags.
+func (f *flags) resolve(options []Option, dir string) ([]Option, error) {
+	configOptions, err := f.loadConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	changeOptions, err := f.loadChanges()
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Concat(options, configOptions, f.options(), changeOptions), nil
+}
+
+// loadConfig loads the configuration files which apply to the directory.
+func (f *flags) loadConfig(dir string) ([]Option, error) {
+	if !f.config.value {
+		return nil, nil
+	}
+
+	type result struct {
+		options []Option
+		err     error
+	}
+	if v, ok := f.configs.Load(dir); ok {
+		r := v.(result)
+		return r.options, r.err
+	}
+
+	options, err := LoadConfig(dir)
+	f.configs.Store(dir, result{options, err})
+
+	return options, err
+}
+
+// loadChanges loads the lines changed by -new-from-rev or -new-from-patch once, it returns nil if neither is set.
+func (f *flags) loadChanges() ([]Option, error) {
+	if f.newFromRev.value == "" && f.newFromPatch.value == "" {
+		return nil, nil
+	}
+
+	f.changesOnce.Do(func() {
+		if f.newFromPatch.value != "" {
+			f.changes, f.changesErr = loadPatch(f.newFromPatch.value)
+		} else {
+			f.changes, f.changesErr = loadRevision(f.newFromRev.value)
+		}
+	})
+	if f.changesErr != nil {
+		return nil, f.changesErr
+	}
+
+	return []Option{WithChangedLines(f.changes.contains)}, nil
+}
+
+// boolFlag is a boolean flag which records whether it's set.
+type boolFlag struct {
+	value bool
+	set   bool
+}
+
+func (f *boolFlag) String() string { return strconv.FormatBool(f.value) }
+
+func (f *boolFlag) IsBoolFlag() bool { return true }
+
+func (f *boolFlag) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	f.value, f.set = v, true
+
+	return nil
+}
+
+// listFlag is a comma separated list flag which records whether it's set.
+type listFlag struct {
+	values []string
+	set    bool
+}
+
+func (f *listFlag) String() string { return strings.Join(f.values, ",") }
+
+func (f *listFlag) Set(s string) error {
+	f.values = nil
+	if s != "" {
+		f.values = strings.Split(s, ",")
+	}
+	f.set = true
+
+	return nil
+}
+
+// stringFlag is a string flag.
+type stringFlag struct {
+	value string
+}
+
+func (f *stringFlag) String() string { return f.value }
+
+func (f *stringFlag) Set(s string) error {
+	f.value = s
+
+	return nil
+}
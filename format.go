@@ -0,0 +1,146 @@
+package tagalign
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+
+	"github.com/alfatraining/structtag"
+)
+
+// Edit is a replacement of the source from Pos to End, applied by formatting.
+type Edit struct {
+	Pos     token.Pos
+	End     token.Pos
+	NewText []byte
+	Message string // the message of the diagnostic fixed by the edit.
+}
+
+// FormatSource aligns and sorts the struct tags of the Go source by the options,
+// and returns the rewritten source. The source only needs to be syntactically valid.
+// The package filters are not applied, the import path of the source being unknown.
+func FormatSource(src []byte, options ...Option) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	edits, err := FormatFile(fset, file, "", options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return ApplyEdits(fset.File(file.Pos()), src, edits), nil
+}
+
+// FormatFile returns the edits aligning and sorting the struct tags of the file by the options,
+// and removing the redundant tags of the structs not rewritten, see WithRedundantCheck.
+// The edits are ordered by position and not overlapping, they are the fixes suggested by the analyzer.
+// The checks reporting tags without a fix, e.g. WithGormCheck, are skipped.
+// pkgPath is the import path of the package of the file matched by the package filters,
+// they are not applied if it's empty, i.e. the import path is unknown.
+func FormatFile(fset *token.FileSet, file *ast.File, pkgPath string, options ...Option) ([]Edit, error) {
+	if fileIgnored(file) {
+		return nil, nil
+	}
+
+	h, err := newFileHelper(getFilename(fset, file), options...)
+	if err != nil {
+		return nil, err
+	}
+	if h.skipped(fset, file) {
+		return nil, nil
+	}
+	rewrite := h.rewrites() && (pkgPath == "" || h.packageMatched(pkgPath))
+	h.rewriting = rewrite
+	if !rewrite && len(h.redundantKeys) == 0 {
+		return nil, nil
+	}
+
+	var edits []Edit
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ignored(n) {
+			return false
+		}
+		if rewrite {
+			h.Find(fset, n)
+		}
+		if st, ok := n.(*ast.StructType); ok {
+			edits = append(edits, h.redundantEdits(fset, st)...)
+		}
+		return true
+	})
+
+	for _, group := range h.Groups() {
+		if !h.groupChanged(fset, group) {
+			continue
+		}
+		for _, tag := range group.Tags {
+			if tag.Err == nil {
+				edits = append(edits, tag.Edit())
+			}
+		}
+	}
+
+	slices.SortStableFunc(edits, func(a, b Edit) int {
+		return int(a.Pos - b.Pos)
+	})
+	var end token.Pos
+	edits = slices.DeleteFunc(edits, func(edit Edit) bool {
+		overlapped := edit.Pos < end
+		if !overlapped {
+			end = edit.End
+		}
+		return overlapped
+	})
+
+	return edits, nil
+}
+
+// redundantEdits returns the edits removing the redundant tags of the struct, unless it's rewritten.
+func (w *Helper) redundantEdits(fset *token.FileSet, st *ast.StructType) []Edit {
+	if len(w.redundantKeys) == 0 || (w.rewriting && w.typeMatched(st)) {
+		return nil
+	}
+
+	var edits []Edit
+	for _, field := range st.Fields.List {
+		if field.Tag == nil || ignored(field) || !w.changed(fset, field.Tag) {
+			continue
+		}
+
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		tags, err := structtag.Parse(tag)
+		if err != nil || tags == nil {
+			continue
+		}
+
+		if edit, ok := w.redundantEdit(field, tag, tags); ok && edit.Pos.IsValid() {
+			edits = append(edits, edit)
+		}
+	}
+
+	return edits
+}
+
+// ApplyEdits returns the source of the file with the edits applied, which must be ordered and not overlapping.
+func ApplyEdits(tf *token.File, src []byte, edits []Edit) []byte {
+	var buf bytes.Buffer
+	var last int
+	for _, edit := range edits {
+		start := tf.Offset(edit.Pos)
+		buf.Write(src[last:start])
+		buf.Write(edit.NewText)
+		last = tf.Offset(edit.End)
+	}
+	buf.Write(src[last:])
+
+	return buf.Bytes()
+}
@@ -0,0 +1,71 @@
+package tagalign
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const formatSrc = `package example
+
+type Example struct {
+	Foo    int    ` + "`yaml:\"foo\" json:\"foo\"`" + `
+	FooBar string ` + "`json:\"foo_bar\" yaml:\"foo_bar\"`" + `
+	Bar    undefined ` + "`json:\"Bar\"`" + `
+}
+`
+
+func TestFormatSource(t *testing.T) {
+	res, err := FormatSource([]byte(formatSrc), WithSort("json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, `package example
+
+type Example struct {
+	Foo    int    `+"`json:\"foo\"     yaml:\"foo\"`"+`
+	FooBar string `+"`json:\"foo_bar\" yaml:\"foo_bar\"`"+`
+	Bar    undefined `+"`json:\"Bar\"`"+`
+}
+`, string(res))
+
+	_, err = FormatSource([]byte("package"))
+	assert.Error(t, err)
+
+	_, err = FormatSource([]byte(formatSrc), WithStrictStyle())
+	assert.ErrorContains(t, err, "invalid options: strict style requires align and sort")
+}
+
+func TestFormatFile(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "example.go", formatSrc, parser.ParseComments)
+	require.NoError(t, err)
+
+	edits, err := FormatFile(fset, file, "", WithSort("json"))
+	require.NoError(t, err)
+	require.Len(t, edits, 1)
+	assert.Equal(t, 4, fset.Position(edits[0].Pos).Line)
+	assert.Equal(t, "`json:\"foo\"     yaml:\"foo\"`", string(edits[0].NewText))
+	assert.Equal(t, `tag is not aligned, should be: json:"foo"     yaml:"foo"`, edits[0].Message)
+
+	edits, err = FormatFile(fset, file, "", WithExcludeFiles("example.go"))
+	require.NoError(t, err)
+	assert.Empty(t, edits)
+
+	edits, err = FormatFile(fset, file, "", WithAlign(false), WithRedundantCheck("json"))
+	require.NoError(t, err)
+	require.Len(t, edits, 1)
+	assert.Equal(t, 6, fset.Position(edits[0].Pos).Line)
+	assert.Empty(t, edits[0].NewText)
+	assert.Equal(t, `tag is redundant, same as the default name of the field: json:"Bar"`, edits[0].Message)
+
+	edits, err = FormatFile(fset, file, "example.com/internal/example", WithSort("json"), WithExcludePackages("/internal/"))
+	require.NoError(t, err)
+	assert.Empty(t, edits)
+
+	edits, err = FormatFile(fset, file, "", WithSort("json"), WithIncludePackages("^example.com/api/"))
+	require.NoError(t, err)
+	assert.Len(t, edits, 1)
+}
@@ -0,0 +1,64 @@
+package tagalign
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// matchFile reports whether the file name matches the pattern. Patterns not starting with a slash
+// match the trailing elements of the name, e.g. `*.pb.go` matches the files in any directory.
+func matchFile(pattern, filename string) bool {
+	name := filepath.ToSlash(filename)
+	if strings.HasPrefix(pattern, "/") {
+		return matchGlob(pattern, name)
+	}
+
+	return matchGlob("**/"+pattern, name)
+}
+
+// matchGlob reports whether name matches the pattern, both separated by slashes.
+// Besides the syntax of path.Match, a `**` element matches any number of path elements,
+// and a pattern matching a directory matches the files in it as well.
+func matchGlob(pattern, name string) bool {
+	return matchElems(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchElems(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			pattern = pattern[1:]
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := range name {
+				if matchElems(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	// the remaining name is the content of a matched directory.
+	return true
+}
+
+// isValidGlob reports whether the pattern is well formed.
+func isValidGlob(pattern string) bool {
+	for _, elem := range strings.Split(pattern, "/") {
+		if _, err := path.Match(elem, ""); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
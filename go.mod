@@ -6,16 +6,16 @@ toolchain go1.22.2
 
 require (
 	github.com/alfatraining/structtag v1.0.0
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/stretchr/testify v1.8.2
+	golang.org/x/mod v0.23.0
 	golang.org/x/tools v0.30.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/kr/pretty v0.3.0 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/mod v0.23.0 // indirect
 	golang.org/x/sync v0.11.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,209 @@
+package tagalign
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// gormDirectives lists the directives known by gorm in their canonical spelling and order.
+var gormDirectives = []gormDirectiveSpec{
+	{"-", gormValueOptional},
+	{"<-", gormValueOptional},
+	{"->", gormValueOptional},
+	{"column", gormValueRequired},
+	{"type", gormValueRequired},
+	{"serializer", gormValueRequired},
+	{"size", gormValueInt},
+	{"precision", gormValueInt},
+	{"scale", gormValueInt},
+	{"primaryKey", gormValueBool},
+	{"unique", gormValueBool},
+	{"not null", gormValueBool},
+	{"autoIncrement", gormValueOptional},
+	{"autoIncrementIncrement", gormValueInt},
+	{"default", gormValueRequired},
+	{"autoCreateTime", gormValueOptional},
+	{"autoUpdateTime", gormValueOptional},
+	{"index", gormValueOptional},
+	{"uniqueIndex", gormValueOptional},
+	{"check", gormValueRequired},
+	{"comment", gormValueRequired},
+	{"embedded", gormValueNone},
+	{"embeddedPrefix", gormValueRequired},
+	{"foreignKey", gormValueRequired},
+	{"references", gormValueRequired},
+	{"polymorphic", gormValueRequired},
+	{"polymorphicType", gormValueRequired},
+	{"polymorphicId", gormValueRequired},
+	{"polymorphicValue", gormValueRequired},
+	{"many2many", gormValueRequired},
+	{"joinForeignKey", gormValueRequired},
+	{"joinReferences", gormValueRequired},
+	{"constraint", gormValueRequired},
+}
+
+// gormAliases maps the alternative spellings accepted by gorm to the canonical ones.
+var gormAliases = map[string]string{
+	"PRIMARY_KEY": "primaryKey",
+	"NOTNULL":     "not null",
+}
+
+type gormDirectiveSpec struct {
+	name  string
+	value gormValue // whether the directive takes a value.
+}
+
+type gormValue int
+
+const (
+	gormValueNone gormValue = iota
+	gormValueOptional
+	gormValueRequired
+	gormValueInt
+	gormValueBool // no value or a boolean value, read by gorm with utils.CheckTruth.
+)
+
+// gormDirective is a single `key:value` directive of a gorm tag.
+type gormDirective struct {
+	key      string // key as written.
+	name     string // canonical name, empty if unknown.
+	value    string // raw value, escaped semicolons are kept.
+	hasValue bool
+	offset   int // byte offset of the directive in the tag value.
+}
+
+// parseGormTag splits a gorm tag value into directives the same way gorm does:
+// directives are separated by semicolons, `\;` escapes a semicolon, keys are case-insensitive.
+func parseGormTag(value string) []gormDirective {
+	var directives []gormDirective
+	for start := 0; start <= len(value); {
+		end := start
+		for end < len(value) && value[end] != ';' {
+			if value[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		end = min(end, len(value))
+
+		raw := value[start:end]
+		if strings.TrimSpace(raw) != "" {
+			key, val, hasValue := strings.Cut(raw, ":")
+			offset := start + len(key) - len(strings.TrimLeft(key, " "))
+			key = strings.TrimSpace(key)
+			directives = append(directives, gormDirective{
+				key:      key,
+				name:     gormCanonicalName(key),
+				value:    strings.TrimSpace(val),
+				hasValue: hasValue,
+				offset:   offset,
+			})
+		}
+
+		start = end + 1
+	}
+
+	return directives
+}
+
+func gormCanonicalName(key string) string {
+	upper := strings.ToUpper(key)
+	if name, ok := gormAliases[upper]; ok {
+		return name
+	}
+	for _, d := range gormDirectives {
+		if strings.ToUpper(d.name) == upper {
+			return d.name
+		}
+	}
+	return ""
+}
+
+func gormDirectiveIndex(name string) int {
+	return slices.IndexFunc(gormDirectives, func(spec gormDirectiveSpec) bool {
+		return spec.name == name
+	})
+}
+
+// checkGorm reports unknown, duplicated and malformed directives of a gorm tag.
+func (w *Helper) checkGorm(pass *analysis.Pass, loc tagLocation, value string) {
+	seen := make(map[string]bool)
+	for _, d := range parseGormTag(value) {
+		var msg string
+		switch {
+		case d.name == "":
+			msg = fmt.Sprintf("unknown gorm directive %q", d.key)
+		case seen[d.name] && d.name != "index" && d.name != "uniqueIndex":
+			// a field may belong to several indexes.
+			msg = fmt.Sprintf("duplicate gorm directive %q", d.key)
+		default:
+			msg = checkGormValue(d)
+		}
+		seen[d.name] = d.name != ""
+
+		if msg != "" {
+			w.reportTagError(pass, loc, d.offset, len(d.key), RuleGorm, msg)
+		}
+	}
+}
+
+func checkGormValue(d gormDirective) string {
+	i := gormDirectiveIndex(d.name)
+	if i == -1 {
+		return ""
+	}
+
+	switch gormDirectives[i].value {
+	case gormValueNone:
+		if d.hasValue {
+			return fmt.Sprintf("gorm directive %q does not take a value", d.key)
+		}
+	case gormValueRequired:
+		if d.value == "" {
+			return fmt.Sprintf("gorm directive %q requires a value", d.key)
+		}
+	case gormValueInt:
+		if _, err := strconv.Atoi(d.value); err != nil {
+			return fmt.Sprintf("gorm directive %q requires an integer value, got %q", d.key, d.value)
+		}
+	case gormValueBool:
+		if d.hasValue && !strings.EqualFold(d.value, "true") && !strings.EqualFold(d.value, "false") {
+			return fmt.Sprintf("gorm directive %q requires a boolean value, got %q", d.key, d.value)
+		}
+	}
+
+	return ""
+}
+
+// canonicalGormTag rewrites a gorm tag value with directives in canonical order and spelling,
+// separated by semicolons without extra spaces. Unknown directives are kept at the end in their original order.
+func canonicalGormTag(value string) string {
+	directives := parseGormTag(value)
+	rank := func(d gormDirective) int {
+		if i := gormDirectiveIndex(d.name); i != -1 {
+			return i
+		}
+		return len(gormDirectives)
+	}
+	slices.SortStableFunc(directives, func(a, b gormDirective) int {
+		return rank(a) - rank(b)
+	})
+
+	parts := make([]string, len(directives))
+	for i, d := range directives {
+		key := d.key
+		if d.name != "" {
+			key = d.name
+		}
+		if d.hasValue {
+			key += ":" + d.value
+		}
+		parts[i] = key
+	}
+
+	return strings.Join(parts, ";")
+}
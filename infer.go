@@ -0,0 +1,79 @@
+package tagalign
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// pair is an ordered pair of tag keys.
+type pair struct {
+	first, second string
+}
+
+// pairCounts returns the number of fields in which a key is placed before another one, for every pair of keys.
+func (s Stats) pairCounts() map[pair]int {
+	counts := make(map[pair]int)
+	for order, n := range s.Orders {
+		keys := strings.Split(order, ",")
+		for i, first := range keys {
+			for _, second := range keys[i+1:] {
+				if first != second {
+					counts[pair{first, second}] += n
+				}
+			}
+		}
+	}
+
+	return counts
+}
+
+// InferOrder returns the order of the tag keys which agrees the most with the orders observed in the fields.
+// It's a tournament between the keys: a key beats another one if it's placed before it in more fields,
+// and the keys are ranked by their number of wins, then by their margins of fields, their number of fields and name.
+func (s Stats) InferOrder() []string {
+	counts := s.pairCounts()
+
+	type score struct {
+		key    string
+		wins   int
+		margin int
+	}
+	scores := make([]score, 0, len(s.Keys))
+	for key := range s.Keys {
+		sc := score{key: key}
+		for other := range s.Keys {
+			before, after := counts[pair{key, other}], counts[pair{other, key}]
+			if before > after {
+				sc.wins++
+			}
+			sc.margin += before - after
+		}
+		scores = append(scores, sc)
+	}
+	slices.SortFunc(scores, func(a, b score) int {
+		return cmp.Or(cmp.Compare(b.wins, a.wins), cmp.Compare(b.margin, a.margin),
+			cmp.Compare(s.Keys[b.key], s.Keys[a.key]), strings.Compare(a.key, b.key))
+	})
+
+	order := make([]string, len(scores))
+	for i, sc := range scores {
+		order[i] = sc.key
+	}
+
+	return order
+}
+
+// Agrees reports whether sorting by the order, see WithSort, agrees with the dominant relative order
+// of every pair of keys observed in the fields, i.e. no key is moved after a key it's placed before in most fields.
+func (s Stats) Agrees(order []string) bool {
+	compare := compareByFixedOrder(order)
+	counts := s.pairCounts()
+	for p, before := range counts {
+		if before > counts[pair{p.second, p.first}] && compare(p.first, p.second) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,69 @@
+package tagalign
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats_InferOrder(t *testing.T) {
+	tests := []struct {
+		desc   string
+		orders map[string]int
+		want   []string
+		agrees bool
+	}{
+		{
+			desc:   "dominant order",
+			orders: map[string]int{"json,yaml": 3, "yaml,json": 1, "yaml,xml": 2, "json,xml": 1},
+			want:   []string{"json", "yaml", "xml"},
+			agrees: true,
+		},
+		{
+			desc:   "cycle ranked by margins",
+			orders: map[string]int{"json,yaml": 3, "yaml,xml": 2, "xml,json": 1},
+			want:   []string{"json", "yaml", "xml"},
+		},
+		{
+			desc:   "keys never together ranked by fields then name",
+			orders: map[string]int{"json": 2, "yaml": 2, "gorm": 3},
+			want:   []string{"gorm", "json", "yaml"},
+			agrees: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var s Stats
+			for order, n := range test.orders {
+				s.Add(statsOf(order, n))
+			}
+
+			assert.Equal(t, test.want, s.InferOrder())
+			assert.Equal(t, test.agrees, s.Agrees(test.want))
+		})
+	}
+}
+
+func TestStats_Agrees(t *testing.T) {
+	var s Stats
+	s.Add(statsOf("gorm,yaml,json", 2))
+	s.Add(statsOf("gorm,json,yaml", 1))
+
+	assert.True(t, s.Agrees([]string{"gorm", "yaml", "json"}))
+	assert.True(t, s.Agrees([]string{"gorm", "yaml"}))
+	assert.False(t, s.Agrees([]string{"gorm"}), "the other keys are sorted by name")
+	assert.False(t, s.Agrees([]string{"gorm", "json"}))
+	assert.False(t, s.Agrees(nil))
+}
+
+// statsOf returns the stats of n fields with the keys in the order.
+func statsOf(order string, n int) Stats {
+	s := Stats{Keys: make(map[string]int), Orders: map[string]int{order: n}}
+	for _, key := range strings.Split(order, ",") {
+		s.Keys[key] += n
+	}
+
+	return s
+}
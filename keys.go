@@ -0,0 +1,80 @@
+package tagalign
+
+import (
+	"fmt"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkKey reports a tag key which is denied, or not in the allowed keys.
+func (w *Helper) checkKey(pass *analysis.Pass, loc tagLocation, key string) {
+	// the key is followed by `:"` before the value.
+	offset := -len(key) - 2
+
+	if msg, ok := w.deniedKeys[key]; ok {
+		if msg == "" {
+			msg = fmt.Sprintf("tag key %q is denied", key)
+		} else {
+			msg = fmt.Sprintf("tag key %q is denied: %s", key, msg)
+		}
+		w.reportTagError(pass, loc, offset, len(key), RuleKeys, msg)
+		return
+	}
+
+	if len(w.allowedKeys) == 0 || slices.Contains(w.allowedKeys, key) {
+		return
+	}
+
+	msg := fmt.Sprintf("tag key %q is not allowed", key)
+	if suggestion := suggestKey(key, w.allowedKeys); suggestion != "" {
+		msg += fmt.Sprintf(", did you mean %q?", suggestion)
+	}
+	w.reportTagError(pass, loc, offset, len(key), RuleKeys, msg)
+}
+
+// suggestKey returns the candidate closest to key, or empty if none of them is close enough to be a typo.
+func suggestKey(key string, candidates []string) string {
+	var suggestion string
+	best := -1
+	for _, c := range candidates {
+		d := editDistance(key, c)
+		if d > max(1, len(key)/3) {
+			continue
+		}
+		if best == -1 || d < best {
+			suggestion, best = c, d
+		}
+	}
+
+	return suggestion
+}
+
+// editDistance returns the optimal string alignment distance between a and b,
+// i.e. the Levenshtein distance with transpositions of adjacent characters.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(ra)][len(rb)]
+}
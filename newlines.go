@@ -0,0 +1,187 @@
+package tagalign
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// changedLines is the set of lines added or modified by a patch, by absolute file name.
+// The lines of a file are nil if all of them are changed, e.g. the file is untracked.
+type changedLines map[string]map[int]bool
+
+// contains reports whether the line of the file is changed.
+func (c changedLines) contains(filename string, line int) bool {
+	lines, ok := c[filepath.Clean(filename)]
+	return ok && (lines == nil || lines[line])
+}
+
+// parsePatch returns the lines added or modified by the unified diff, in the new version of the files.
+// The file names of the diff are resolved relative to dir, the `b/` prefix of git is removed.
+func parsePatch(r io.Reader, dir string) (changedLines, error) {
+	changed := make(changedLines)
+
+	var lines map[int]bool
+	var line int
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			name, _, _ := strings.Cut(strings.TrimPrefix(text, "+++ "), "\t")
+			lines = nil
+			if name != "/dev/null" {
+				name = filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(name, "b/")))
+				lines = make(map[int]bool)
+				changed[filepath.Clean(name)] = lines
+			}
+		case strings.HasPrefix(text, "--- "):
+		case strings.HasPrefix(text, "@@ "):
+			// @@ -l,s +l,s @@
+			fields := strings.Fields(text)
+			if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+				return nil, fmt.Errorf("invalid hunk header %q", text)
+			}
+			start, _, _ := strings.Cut(fields[2][1:], ",")
+			n, err := strconv.Atoi(start)
+			if err != nil {
+				return nil, fmt.Errorf("invalid hunk header %q", text)
+			}
+			line = n
+		case lines == nil || line == 0:
+			// the header of a file.
+		case strings.HasPrefix(text, "+"):
+			lines[line] = true
+			line++
+		case strings.HasPrefix(text, " "), text == "":
+			line++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return changed, nil
+}
+
+// loadPatch reads the unified diff from the file, the names in it are relative to the working directory.
+func loadPatch(file string) (changedLines, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePatch(f, dir)
+}
+
+// loadRevision returns the lines changed in the working tree since the git revision,
+// the untracked files which are not ignored are changed as a whole.
+func loadRevision(rev string) (changedLines, error) {
+	out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, fmt.Errorf("can't find the git repository: %w", err)
+	}
+	root := strings.TrimSpace(string(out))
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "diff", "--no-color", "--no-ext-diff", "--unified=0", rev, "--")
+	cmd.Dir = root
+	cmd.Stderr = &stderr
+	out, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("can't diff with revision %s: %w: %s", rev, err, strings.TrimSpace(stderr.String()))
+	}
+	changed, err := parsePatch(bytes.NewReader(out), root)
+	if err != nil {
+		return nil, err
+	}
+
+	stderr.Reset()
+	cmd = exec.Command("git", "ls-files", "--others", "--exclude-standard", "-z")
+	cmd.Dir = root
+	cmd.Stderr = &stderr
+	out, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("can't list the untracked files: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name != "" {
+			changed[filepath.Join(root, filepath.FromSlash(name))] = nil
+		}
+	}
+
+	return changed, nil
+}
+
+// linesChanged reports whether any line from start to end is changed, all lines are changed without WithChangedLines.
+func (w *Helper) linesChanged(start, end int) bool {
+	if w.changedLines == nil {
+		return true
+	}
+	for line := start; line <= end; line++ {
+		if w.changedLines(w.filename, line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// changed reports whether any line of the node is changed.
+func (w *Helper) changed(fset *token.FileSet, n ast.Node) bool {
+	return w.linesChanged(fset.Position(n.Pos()).Line, fset.Position(n.End()).Line)
+}
+
+// groupChanged reports whether any field of the group is changed,
+// all the tags of such group are reported as the alignment depends on every field.
+func (w *Helper) groupChanged(fset *token.FileSet, group Group) bool {
+	for _, field := range group.Fields {
+		if w.changed(fset, field) {
+			return true
+		}
+	}
+	for _, tag := range group.Tags {
+		if w.changed(fset, tag.Field) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// changedPass returns the pass reporting only the diagnostics on changed lines.
+func (w *Helper) changedPass(pass *analysis.Pass) *analysis.Pass {
+	if w.changedLines == nil {
+		return pass
+	}
+
+	p := *pass
+	p.Report = func(d analysis.Diagnostic) {
+		end := d.End
+		if !end.IsValid() {
+			end = d.Pos
+		}
+		if w.linesChanged(pass.Fset.Position(d.Pos).Line, pass.Fset.Position(end).Line) {
+			pass.Report(d)
+		}
+	}
+
+	return &p
+}
@@ -1,7 +1,88 @@
 package tagalign
 
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
 type Option func(*Helper)
 
+// ValidateOptions returns an error describing the invalid options, e.g. duplicate keys in the order
+// or strict style without sort. The analyzer returns the same error instead of running with such options.
+func ValidateOptions(options ...Option) error {
+	return newHelper("", options...).validate()
+}
+
+// validate returns an error describing the invalid settings of the helper.
+func (w *Helper) validate() error {
+	var errs []error
+	if w.style == StrictStyle && (!w.align || !w.sort) {
+		errs = append(errs, errors.New("strict style requires align and sort"))
+	}
+	if len(w.fixedTagOrder) > 0 && !w.sort {
+		errs = append(errs, errors.New("order requires sort"))
+	}
+	errs = append(errs, w.validateOrder(w.fixedTagOrder)...)
+	for _, key := range w.allowedKeys {
+		if hasKey(w.deniedKeys, key) {
+			errs = append(errs, fmt.Errorf("tag key %q is both allowed and denied", key))
+		}
+	}
+	for _, pattern := range slices.Concat(w.includeFiles, w.excludeFiles) {
+		if !isValidGlob(pattern) {
+			errs = append(errs, fmt.Errorf("invalid file pattern %q", pattern))
+		}
+	}
+	for _, pattern := range slices.Concat(w.includeTypes, w.excludeTypes, w.includePackages, w.excludePackages) {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("invalid pattern %q: %w", pattern, err))
+		}
+	}
+	for _, key := range w.redundantKeys {
+		if _, ok := defaultNames[key]; !ok {
+			errs = append(errs, fmt.Errorf("redundant check doesn't support tag key %q", key))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateOrder returns the errors of the keys of the order, also used for the orders of directives.
+func (w *Helper) validateOrder(order []string) []error {
+	var errs []error
+	for i, key := range order {
+		switch {
+		case !isValidTagKey(key):
+			errs = append(errs, fmt.Errorf("invalid tag key %q in order", key))
+		case slices.Contains(order[:i], key):
+			errs = append(errs, fmt.Errorf("duplicate tag key %q in order", key))
+		case len(w.allowedKeys) > 0 && !slices.Contains(w.allowedKeys, key):
+			errs = append(errs, fmt.Errorf("tag key %q in order is not allowed", key))
+		case hasKey(w.deniedKeys, key):
+			errs = append(errs, fmt.Errorf("tag key %q in order is denied", key))
+		case slices.Contains(w.unalignedKeys, key):
+			errs = append(errs, fmt.Errorf("tag key %q is both in order and unaligned", key))
+		}
+	}
+
+	return errs
+}
+
+// isValidTagKey reports whether key is a valid key of a struct tag, as parsed by reflect.StructTag.
+func isValidTagKey(key string) bool {
+	return key != "" && !strings.ContainsFunc(key, func(r rune) bool {
+		return r <= ' ' || r == ':' || r == '"' || r == 0x7f
+	})
+}
+
+func hasKey(m map[string]string, key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
 // WithSort enable tags sort.
 // fixedOrder specify the order of tags, the other tags will be sorted by name.
 // Sory is disabled by default.
@@ -22,9 +103,147 @@ func WithAlign(enabled bool) Option {
 
 // WithStrictStyle configure whether enable strict style.
 // StrictStyle is disabled by default.
-// Note: StrictStyle must be used with WithAlign(true) and WithSort(...) together, or it will be ignored.
+// Note: StrictStyle must be used with WithAlign(true) and WithSort(...) together, see ValidateOptions.
 func WithStrictStyle() Option {
 	return func(h *Helper) {
 		h.style = StrictStyle
 	}
 }
+
+// WithDuplicateNameCheck enable reporting fields serialized to the same name.
+// keys specify the tag keys to check, e.g. "json", "yaml". Names are resolved by the rules of encoding/json,
+// including the fields promoted from embedded structs.
+// Duplicate name check is disabled by default.
+func WithDuplicateNameCheck(keys ...string) Option {
+	return func(h *Helper) {
+		h.duplicateKeys = keys
+	}
+}
+
+// WithGormCheck enable validating the syntax and directives of gorm tags.
+// If canonical is true, the directives of gorm tags are also rewritten in canonical order and spelling.
+// Gorm check is disabled by default.
+func WithGormCheck(canonical bool) Option {
+	return func(h *Helper) {
+		h.gormCheck = true
+		h.gormCanonical = canonical
+	}
+}
+
+// WithValidateCheck enable validating the rules of go-playground/validator tags, i.e. `validate` and `binding`.
+// customTags specify the tags registered to the validator besides the baked-in ones.
+// Validate check is disabled by default.
+func WithValidateCheck(customTags ...string) Option {
+	return func(h *Helper) {
+		h.validateCheck = true
+		h.validateCustomTags = customTags
+	}
+}
+
+// WithAllowedKeys specify the tag keys allowed, the other keys are reported
+// with a suggestion if it looks like a typo of an allowed key.
+// Any key is allowed by default.
+func WithAllowedKeys(keys ...string) Option {
+	return func(h *Helper) {
+		h.allowedKeys = keys
+	}
+}
+
+// WithDeniedKeys specify the tag keys denied, mapped to the message reported with them, which can be empty.
+// No key is denied by default.
+func WithDeniedKeys(keys map[string]string) Option {
+	return func(h *Helper) {
+		h.deniedKeys = keys
+	}
+}
+
+// WithRedundantCheck enable reporting tags equal to the default behavior of their encoders,
+// e.g. `json:"Name"` on the field Name, or `yaml:"name"` since yaml lowercases the field name.
+// keys specify the tag keys to check, supported keys are json, xml, toml, mapstructure, yaml and bson.
+// Tags with options, e.g. `json:"Name,omitempty"`, are never redundant.
+// Redundant check is disabled by default.
+func WithRedundantCheck(keys ...string) Option {
+	return func(h *Helper) {
+		h.redundantKeys = keys
+	}
+}
+
+// WithGeneratedFiles configure whether check generated files,
+// i.e. files with a `// Code generated ... DO NOT EDIT.` comment, see ast.IsGenerated.
+// Generated files are skipped by default.
+func WithGeneratedFiles(enabled bool) Option {
+	return func(h *Helper) {
+		h.generated = enabled
+	}
+}
+
+// WithIncludeFiles specify the glob patterns of the files checked, the other files are skipped.
+// Patterns are separated by slashes and match the trailing elements of the file name unless they start with a slash,
+// e.g. "*.go" or "internal/**". `**` matches any number of directories.
+// All files are checked by default.
+func WithIncludeFiles(patterns ...string) Option {
+	return func(h *Helper) {
+		h.includeFiles = patterns
+	}
+}
+
+// WithExcludeFiles specify the glob patterns of the files skipped, e.g. "*.pb.go", see WithIncludeFiles for the syntax.
+// No file is excluded by default.
+func WithExcludeFiles(patterns ...string) Option {
+	return func(h *Helper) {
+		h.excludeFiles = patterns
+	}
+}
+
+// WithIncludeTypes specify the regular expressions of the type names aligned, e.g. ".*(Request|Response)$",
+// the other structs are not aligned nor sorted. Nested structs are matched by the name of the enclosing type,
+// and structs outside of a type declaration by an empty name.
+// All types are aligned by default.
+func WithIncludeTypes(patterns ...string) Option {
+	return func(h *Helper) {
+		h.includeTypes = patterns
+	}
+}
+
+// WithExcludeTypes specify the regular expressions of the type names not aligned, see WithIncludeTypes.
+// No type is excluded by default.
+func WithExcludeTypes(patterns ...string) Option {
+	return func(h *Helper) {
+		h.excludeTypes = patterns
+	}
+}
+
+// WithIncludePackages specify the regular expressions of the package paths aligned,
+// the structs of the other packages are not aligned nor sorted.
+// All packages are aligned by default.
+func WithIncludePackages(patterns ...string) Option {
+	return func(h *Helper) {
+		h.includePackages = patterns
+	}
+}
+
+// WithExcludePackages specify the regular expressions of the package paths not aligned.
+// No package is excluded by default.
+func WithExcludePackages(patterns ...string) Option {
+	return func(h *Helper) {
+		h.excludePackages = patterns
+	}
+}
+
+// WithUnalignedKeys specify the tag keys excluded from alignment, e.g. "validate" whose long values would widen the columns.
+// Such tags are kept but placed at the end of the tags, separated by a single space.
+// All keys are aligned by default.
+func WithUnalignedKeys(keys ...string) Option {
+	return func(h *Helper) {
+		h.unalignedKeys = keys
+	}
+}
+
+// WithChangedLines specify the lines changed, only the groups of fields touching a changed line are reported,
+// so that a stricter style can be adopted incrementally. changed is called with the absolute name of the file.
+// All lines are reported by default.
+func WithChangedLines(changed func(filename string, line int) bool) Option {
+	return func(h *Helper) {
+		h.changedLines = changed
+	}
+}
@@ -0,0 +1,122 @@
+package tagalign
+
+import (
+	"go/ast"
+	"slices"
+	"strings"
+
+	"github.com/alfatraining/structtag"
+	"golang.org/x/tools/go/analysis"
+)
+
+// defaultNames maps tag keys to the name used by their encoders when the tag is absent.
+var defaultNames = map[string]func(fieldName string) string{
+	"json":         func(name string) string { return name },
+	"xml":          func(name string) string { return name },
+	"toml":         func(name string) string { return name },
+	"mapstructure": func(name string) string { return name },
+	"yaml":         strings.ToLower,
+	"bson":         strings.ToLower,
+}
+
+// isRedundant reports whether the tag has the same effect as the default behavior of its encoder,
+// i.e. it only names the field by its default name, without any option.
+func (w *Helper) isRedundant(field *ast.Field, tag *structtag.Tag) bool {
+	if !slices.Contains(w.redundantKeys, tag.Key) || len(field.Names) != 1 {
+		return false
+	}
+
+	defaultName, ok := defaultNames[tag.Key]
+	if !ok {
+		return false
+	}
+
+	return tag.Value == defaultName(field.Names[0].Name)
+}
+
+// removeRedundantTags removes the redundant tags, it reports whether any tag is removed.
+func (w *Helper) removeRedundantTags(field *ast.Field, tags *structtag.Tags) bool {
+	if len(w.redundantKeys) == 0 {
+		return false
+	}
+
+	var kept []string
+	for _, t := range tags.Tags() {
+		if !w.isRedundant(field, t) {
+			kept = append(kept, t.String())
+		}
+	}
+	if len(kept) == tags.Len() {
+		return false
+	}
+
+	newTags, err := structtag.Parse(strings.Join(kept, " "))
+	if err != nil || newTags == nil {
+		newTags = &structtag.Tags{}
+	}
+	*tags = *newTags
+
+	return true
+}
+
+// checkRedundant reports the tags of a field equal to the default behavior of their encoders.
+// If the struct is rewritten by align or sort, the redundant tags are removed by the rewrite,
+// otherwise fix is true and a fix removing the redundant tags only is suggested.
+func (w *Helper) checkRedundant(pass *analysis.Pass, field *ast.Field, tag string, tags *structtag.Tags, fix bool) {
+	edit, ok := w.redundantEdit(field, tag, tags)
+	if !ok {
+		return
+	}
+
+	d := analysis.Diagnostic{
+		Pos:      field.Tag.Pos(),
+		End:      field.Tag.End(),
+		Category: RuleRedundant,
+		Message:  edit.Message,
+	}
+	if fix && edit.Pos.IsValid() {
+		d.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   edit.Message,
+			TextEdits: []analysis.TextEdit{{Pos: edit.Pos, End: edit.End, NewText: edit.NewText}},
+		}}
+	}
+
+	pass.Report(d)
+}
+
+// redundantEdit returns the edit removing the redundant tags of a field only, it reports whether any tag is redundant.
+// The edit has no position if the tag is not a raw string, which can't be rewritten in place.
+func (w *Helper) redundantEdit(field *ast.Field, tag string, tags *structtag.Tags) (Edit, bool) {
+	var redundant []string
+	newTag := tag
+	for _, t := range tags.Tags() {
+		if !w.isRedundant(field, t) {
+			continue
+		}
+		redundant = append(redundant, t.String())
+
+		if offset := tagValueOffset(newTag, t.Key); offset >= 0 {
+			start := offset - len(t.Key) - 2
+			end := offset + len(t.Value) + 1
+			newTag = newTag[:start] + strings.TrimLeft(newTag[end:], " ")
+		}
+	}
+	if len(redundant) == 0 {
+		return Edit{}, false
+	}
+
+	edit := Edit{Message: "tag is redundant, same as the default name of the field: " + strings.Join(redundant, " ")}
+	if !strings.HasPrefix(field.Tag.Value, "`") {
+		return edit, true
+	}
+
+	newTag = strings.TrimSpace(newTag)
+	if newTag == "" {
+		// remove the whole tag literal.
+		edit.Pos, edit.End = field.Type.End(), field.Tag.End()
+	} else {
+		edit.Pos, edit.End, edit.NewText = field.Tag.Pos(), field.Tag.End(), []byte("`"+newTag+"`")
+	}
+
+	return edit, true
+}
@@ -0,0 +1,143 @@
+package tagalign
+
+import (
+	"errors"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Settings are the settings of the analyzer in a serializable form,
+// for tools embedding the analyzer with their own configuration, e.g. golangci-lint.
+// The zero value disables every check, use DefaultSettings to start from the defaults of NewAnalyzer.
+type Settings struct {
+	// Align enables tags align.
+	Align bool `json:"align" yaml:"align" mapstructure:"align"`
+	// Sort enables tags sort.
+	Sort bool `json:"sort" yaml:"sort" mapstructure:"sort"`
+	// Order specifies the order of tags when sorting, the other tags are sorted by name.
+	Order []string `json:"order" yaml:"order" mapstructure:"order"`
+	// Strict enables strict style, it requires Align and Sort.
+	Strict bool `json:"strict" yaml:"strict" mapstructure:"strict"`
+
+	// UnalignedKeys specifies the tag keys excluded from alignment, placed at the end of the tags.
+	UnalignedKeys []string `json:"unaligned-keys" yaml:"unaligned-keys" mapstructure:"unaligned-keys"`
+
+	// Duplicate specifies the tag keys checked for duplicate serialized names.
+	Duplicate []string `json:"duplicate" yaml:"duplicate" mapstructure:"duplicate"`
+	// Gorm enables gorm tags validation.
+	Gorm bool `json:"gorm" yaml:"gorm" mapstructure:"gorm"`
+	// GormCanonical rewrites gorm tags in canonical order and spelling, it implies Gorm.
+	GormCanonical bool `json:"gorm-canonical" yaml:"gorm-canonical" mapstructure:"gorm-canonical"`
+	// Validate enables validate and binding tags validation.
+	Validate bool `json:"validate" yaml:"validate" mapstructure:"validate"`
+	// ValidateTags specifies the custom tags registered to the validator.
+	ValidateTags []string `json:"validate-tags" yaml:"validate-tags" mapstructure:"validate-tags"`
+	// AllowedKeys specifies the tag keys allowed, any key is allowed if empty.
+	AllowedKeys []string `json:"allowed-keys" yaml:"allowed-keys" mapstructure:"allowed-keys"`
+	// DeniedKeys specifies the tag keys denied, mapped to the message reported with them.
+	DeniedKeys map[string]string `json:"denied-keys" yaml:"denied-keys" mapstructure:"denied-keys"`
+	// Redundant specifies the tag keys checked for tags equal to the default behavior.
+	Redundant []string `json:"redundant" yaml:"redundant" mapstructure:"redundant"`
+
+	// Generated enables checking generated files, which are skipped otherwise.
+	Generated bool `json:"generated" yaml:"generated" mapstructure:"generated"`
+	// IncludeFiles specifies the glob patterns of the files checked, all files are checked if empty.
+	IncludeFiles []string `json:"include-files" yaml:"include-files" mapstructure:"include-files"`
+	// ExcludeFiles specifies the glob patterns of the files skipped.
+	ExcludeFiles []string `json:"exclude-files" yaml:"exclude-files" mapstructure:"exclude-files"`
+	// IncludeTypes specifies the regular expressions of the type names aligned, all types are aligned if empty.
+	IncludeTypes []string `json:"include-types" yaml:"include-types" mapstructure:"include-types"`
+	// ExcludeTypes specifies the regular expressions of the type names not aligned.
+	ExcludeTypes []string `json:"exclude-types" yaml:"exclude-types" mapstructure:"exclude-types"`
+	// IncludePackages specifies the regular expressions of the package paths aligned, all packages are aligned if empty.
+	IncludePackages []string `json:"include-packages" yaml:"include-packages" mapstructure:"include-packages"`
+	// ExcludePackages specifies the regular expressions of the package paths not aligned.
+	ExcludePackages []string `json:"exclude-packages" yaml:"exclude-packages" mapstructure:"exclude-packages"`
+}
+
+// DefaultSettings returns the settings equivalent to NewAnalyzer without options.
+func DefaultSettings() Settings {
+	return Settings{Align: true}
+}
+
+// NewAnalyzerFromSettings returns an analyzer configured by the settings,
+// or an error if the settings are invalid.
+func NewAnalyzerFromSettings(s Settings) (*analysis.Analyzer, error) {
+	if err := s.Check(); err != nil {
+		return nil, err
+	}
+
+	return NewAnalyzer(s.Options()...), nil
+}
+
+// Check returns an error describing the invalid combinations of the settings,
+// including the ones reported by ValidateOptions.
+func (s Settings) Check() error {
+	var errs []error
+	if len(s.ValidateTags) > 0 && !s.Validate {
+		errs = append(errs, errors.New("validate-tags requires validate"))
+	}
+	if err := ValidateOptions(s.Options()...); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Options returns the options equivalent to the settings.
+func (s Settings) Options() []Option {
+	options := []Option{WithAlign(s.Align)}
+	if s.Sort {
+		options = append(options, WithSort(s.Order...))
+	} else if len(s.Order) > 0 {
+		// reported by ValidateOptions.
+		options = append(options, func(h *Helper) { h.fixedTagOrder = s.Order })
+	}
+	if s.Strict {
+		options = append(options, WithStrictStyle())
+	}
+	if len(s.UnalignedKeys) > 0 {
+		options = append(options, WithUnalignedKeys(s.UnalignedKeys...))
+	}
+	if len(s.Duplicate) > 0 {
+		options = append(options, WithDuplicateNameCheck(s.Duplicate...))
+	}
+	if s.Gorm || s.GormCanonical {
+		options = append(options, WithGormCheck(s.GormCanonical))
+	}
+	if s.Validate {
+		options = append(options, WithValidateCheck(s.ValidateTags...))
+	}
+	if len(s.AllowedKeys) > 0 {
+		options = append(options, WithAllowedKeys(s.AllowedKeys...))
+	}
+	if len(s.DeniedKeys) > 0 {
+		options = append(options, WithDeniedKeys(s.DeniedKeys))
+	}
+	if len(s.Redundant) > 0 {
+		options = append(options, WithRedundantCheck(s.Redundant...))
+	}
+	if s.Generated {
+		options = append(options, WithGeneratedFiles(true))
+	}
+	if len(s.IncludeFiles) > 0 {
+		options = append(options, WithIncludeFiles(s.IncludeFiles...))
+	}
+	if len(s.ExcludeFiles) > 0 {
+		options = append(options, WithExcludeFiles(s.ExcludeFiles...))
+	}
+	if len(s.IncludeTypes) > 0 {
+		options = append(options, WithIncludeTypes(s.IncludeTypes...))
+	}
+	if len(s.ExcludeTypes) > 0 {
+		options = append(options, WithExcludeTypes(s.ExcludeTypes...))
+	}
+	if len(s.IncludePackages) > 0 {
+		options = append(options, WithIncludePackages(s.IncludePackages...))
+	}
+	if len(s.ExcludePackages) > 0 {
+		options = append(options, WithExcludePackages(s.ExcludePackages...))
+	}
+
+	return options
+}
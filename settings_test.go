@@ -0,0 +1,52 @@
+package tagalign
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestNewAnalyzerFromSettings(t *testing.T) {
+	s := DefaultSettings()
+	s.Sort = true
+	s.Order = []string{"json", "yaml", "xml"}
+	s.Strict = true
+
+	a, err := NewAnalyzerFromSettings(s)
+	require.NoError(t, err)
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, "strict")
+}
+
+func TestSettings_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		err      string
+	}{
+		{name: "default", settings: DefaultSettings()},
+		{name: "strict without sort", settings: Settings{Align: true, Strict: true}, err: "strict style requires align and sort"},
+		{name: "strict without align", settings: Settings{Sort: true, Strict: true}, err: "strict style requires align and sort"},
+		{name: "order without sort", settings: Settings{Align: true, Order: []string{"json"}}, err: "order requires sort"},
+		{name: "validate tags without validate", settings: Settings{ValidateTags: []string{"is_code"}}, err: "validate-tags requires validate"},
+		{
+			name:     "allowed and denied",
+			settings: Settings{AllowedKeys: []string{"json"}, DeniedKeys: map[string]string{"json": ""}},
+			err:      `tag key "json" is both allowed and denied`,
+		},
+		{name: "unsupported redundant key", settings: Settings{Redundant: []string{"gorm"}}, err: `redundant check doesn't support tag key "gorm"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Check()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
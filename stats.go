@@ -0,0 +1,147 @@
+package tagalign
+
+import (
+	"cmp"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// Stats is the summary of the struct tags found in some files, see FileStats.
+type Stats struct {
+	Structs          int            `json:"structs"`          // the structs scanned.
+	Groups           int            `json:"groups"`           // the groups of tagged fields, including single fields.
+	MisalignedGroups int            `json:"misalignedGroups"` // the groups with at least a tag not formatted.
+	MisorderedTags   int            `json:"misorderedTags"`   // the tags whose keys are not sorted.
+	SyntaxErrors     int            `json:"syntaxErrors"`     // the tags which can't be parsed.
+	Keys             map[string]int `json:"keys"`             // the number of fields by tag key.
+	Orders           map[string]int `json:"orders"`           // the number of fields by order of their keys, e.g. "json,yaml".
+}
+
+// Add adds the stats of other files.
+func (s *Stats) Add(other Stats) {
+	s.Structs += other.Structs
+	s.Groups += other.Groups
+	s.MisalignedGroups += other.MisalignedGroups
+	s.MisorderedTags += other.MisorderedTags
+	s.SyntaxErrors += other.SyntaxErrors
+	s.Keys = addCounts(s.Keys, other.Keys)
+	s.Orders = addCounts(s.Orders, other.Orders)
+}
+
+// Count is the number of occurrences of a tag key or order.
+type Count struct {
+	Name  string
+	Count int
+}
+
+// TopKeys returns the n most common tag keys, all keys if n is negative.
+func (s Stats) TopKeys(n int) []Count {
+	return topCounts(s.Keys, n)
+}
+
+// TopOrders returns the n most common orders of the tag keys, all orders if n is negative.
+func (s Stats) TopOrders(n int) []Count {
+	return topCounts(s.Orders, n)
+}
+
+func addCounts(counts, other map[string]int) map[string]int {
+	if counts == nil {
+		counts = make(map[string]int, len(other))
+	}
+	for name, n := range other {
+		counts[name] += n
+	}
+
+	return counts
+}
+
+// topCounts returns the n largest counts, ordered by count then by name.
+func topCounts(counts map[string]int, n int) []Count {
+	top := make([]Count, 0, len(counts))
+	for name, n := range counts {
+		top = append(top, Count{name, n})
+	}
+	slices.SortFunc(top, func(a, b Count) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Name, b.Name))
+	})
+	if n >= 0 && len(top) > n {
+		top = top[:n]
+	}
+
+	return top
+}
+
+// FileStats returns the stats of the struct tags of the file formatted by the options, e.g. to measure the impact of a style.
+// Like FormatFile, it only needs the syntax of the file. The file is skipped like the analyzer does,
+// by the file patterns and the package filters matched against pkgPath.
+func FileStats(fset *token.FileSet, file *ast.File, pkgPath string, options ...Option) (Stats, error) {
+	if fileIgnored(file) {
+		return Stats{}, nil
+	}
+
+	h, err := newFileHelper(getFilename(fset, file), options...)
+	if err != nil {
+		return Stats{}, err
+	}
+	if h.skipped(fset, file) || !h.packageMatched(pkgPath) {
+		return Stats{}, nil
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ignored(n) {
+			return false
+		}
+		h.Find(fset, n)
+		return true
+	})
+
+	var groups []Group
+	for _, group := range h.Groups() {
+		if h.groupChanged(fset, group) {
+			groups = append(groups, group)
+		}
+	}
+
+	return h.groupStats(groups), nil
+}
+
+// groupStats returns the stats of the groups found in the file.
+func (w *Helper) groupStats(groups []Group) Stats {
+	s := Stats{Structs: w.structs, Groups: len(groups), Keys: make(map[string]int), Orders: make(map[string]int)}
+	for _, group := range groups {
+		misaligned := false
+		for _, tag := range group.Tags {
+			switch tag.Rule {
+			case RuleSyntax:
+				s.SyntaxErrors++
+				continue
+			case RuleOrder:
+				s.MisorderedTags++
+			}
+			misaligned = true
+		}
+		if misaligned {
+			s.MisalignedGroups++
+		}
+
+		for _, field := range group.Fields {
+			tags, invalid := parseTag(field)
+			if invalid != nil {
+				continue
+			}
+			var keys []string
+			for _, tag := range tags.Tags() {
+				keys = append(keys, tag.Key)
+				s.Keys[tag.Key]++
+			}
+			if len(keys) == 0 {
+				continue
+			}
+			s.Orders[strings.Join(keys, ",")]++
+		}
+	}
+
+	return s
+}
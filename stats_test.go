@@ -0,0 +1,52 @@
+package tagalign
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStats(t *testing.T) {
+	src := `package example
+
+type Example struct {
+	Foo    int    ` + "`yaml:\"foo\" json:\"foo\"`" + `
+	FooBar string ` + "`json:\"foo_bar\" yaml:\"foo_bar\"`" + `
+
+	Bar int ` + "`json:\"bar`" + `
+	Baz struct {
+		Qux int ` + "`json:\"qux\"`" + `
+	} ` + "`json:\"baz\"`" + `
+}
+
+type Empty struct{}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "/src/example.go", src, parser.ParseComments)
+	require.NoError(t, err)
+
+	stats, err := FileStats(fset, file, "example", WithSort("json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, Stats{
+		Structs:          3,
+		Groups:           3,
+		MisalignedGroups: 1,
+		MisorderedTags:   1,
+		SyntaxErrors:     1,
+		Keys:             map[string]int{"json": 3, "yaml": 2},
+		Orders:           map[string]int{"json": 1, "json,yaml": 1, "yaml,json": 1},
+	}, stats)
+	assert.Equal(t, []Count{{"json", 3}, {"yaml", 2}}, stats.TopKeys(-1))
+	assert.Equal(t, []Count{{"json", 1}}, stats.TopOrders(1))
+
+	stats, err = FileStats(fset, file, "example", WithExcludePackages("^example$"))
+	require.NoError(t, err)
+	assert.Zero(t, stats)
+
+	_, err = FileStats(fset, file, "example", WithStrictStyle())
+	assert.ErrorContains(t, err, "invalid options for /src/example.go")
+}
@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
-	"reflect"
+	"os"
+	"path/filepath"
 	"slices"
-	"strconv"
 	"strings"
 
 	"github.com/alfatraining/structtag"
@@ -26,317 +26,276 @@ const (
 )
 
 func NewAnalyzer(options ...Option) *analysis.Analyzer {
-	return &analysis.Analyzer{
+	return NewLinter(options...).Analyzer()
+}
+
+// Linter is an analyzer with the options it runs with, so that drivers which don't load packages,
+// e.g. with FormatFile, are configured like the analyzer.
+type Linter struct {
+	analyzer *analysis.Analyzer
+	flags    *flags
+	options  []Option
+}
+
+// NewLinter returns the linter running with the options, then the configuration files and the flags of its analyzer.
+func NewLinter(options ...Option) *Linter {
+	l := &Linter{flags: &flags{}, options: options}
+	l.analyzer = &analysis.Analyzer{
 		Name: "tagalign",
 		Doc:  "check that struct tags are well aligned",
 		Run: func(p *analysis.Pass) (any, error) {
-			Run(p, options...)
-			return nil, nil
+			if len(p.Files) == 0 {
+				return nil, nil
+			}
+
+			opts, err := l.Options(getFilename(p.Fset, p.Files[0]))
+			if err != nil {
+				return nil, err
+			}
+			return nil, Run(p, opts...)
 		},
 	}
+	l.flags.register(&l.analyzer.Flags)
+
+	return l
+}
+
+// Analyzer returns the analyzer of the linter.
+func (l *Linter) Analyzer() *analysis.Analyzer {
+	return l.analyzer
+}
+
+// Options returns the options the analyzer runs with for the file: the options of NewLinter,
+// then the configuration files of its directory and the flags set.
+func (l *Linter) Options(filename string) ([]Option, error) {
+	return l.flags.resolve(l.options, filepath.Dir(filename))
+}
+
+// Validate returns an error if the options the analyzer runs with in the working directory are invalid,
+// so that drivers report invalid flags once before running. The configuration files of other directories
+// are validated when the analyzer runs on their files.
+func (l *Linter) Validate() error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	options, err := l.flags.resolve(l.options, dir)
+	if err != nil {
+		return err
+	}
+	_, err = NewHelper(options...)
+
+	return err
 }
 
-func Run(pass *analysis.Pass, options ...Option) {
+// Run reports the struct tags of the files of the pass which don't follow the options.
+// It returns an error if the options resolved for a file are invalid, see ValidateOptions.
+func Run(pass *analysis.Pass, options ...Option) error {
 	for _, f := range pass.Files {
 		filename := getFilename(pass.Fset, f)
-		if !strings.HasSuffix(filename, ".go") {
+		if !strings.HasSuffix(filename, ".go") || fileIgnored(f) {
 			continue
 		}
 
-		h := &Helper{
-			style: DefaultStyle,
-			align: true,
-		}
-		for _, opt := range options {
-			opt(h)
+		if err := runFile(pass, f, filename, options...); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
+
+// runFile reports the struct tags of the file which don't follow the options.
+func runFile(pass *analysis.Pass, f *ast.File, filename string, options ...Option) error {
+	h, err := newFileHelper(filename, options...)
+	if err != nil {
+		return err
+	}
+	if h.skipped(pass.Fset, f) {
+		return nil
+	}
 
-		//  StrictStyle must be used with WithAlign(true) and WithSort(...) together, or it will be ignored.
-		if h.style == StrictStyle && (!h.align || !h.sort) {
-			h.style = DefaultStyle
+	rewrite := h.rewrites() && h.packageMatched(pass.Pkg.Path())
+	h.rewriting = rewrite
+	if !rewrite && len(h.duplicateKeys) == 0 && !h.validating() {
+		// do nothing
+		return nil
+	}
+
+	checkPass := h.changedPass(pass)
+	h.checkDirectives(checkPass, f)
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		if ignored(n) {
+			return false
+		}
+		if rewrite {
+			h.Find(pass.Fset, n)
 		}
+		h.checkDuplicateNames(checkPass, n)
+		h.checkTags(checkPass, n)
+		return true
+	})
+
+	h.Process(pass)
 
-		if !h.align && !h.sort {
-			// do nothing
-			return
+	return nil
+}
+
+// NewHelper returns the engine aligning the tags by the options, or an error if the options are invalid.
+// The groups of tagged fields are found by calling Find on the nodes of a file, then formatted by Groups.
+func NewHelper(options ...Option) (*Helper, error) {
+	return newFileHelper("", options...)
+}
+
+// newFileHelper returns the helper for the file, or an error if the options resolved for it are invalid.
+func newFileHelper(filename string, options ...Option) (*Helper, error) {
+	h := newHelper(filename, options...)
+	if err := h.validate(); err != nil {
+		if filename == "" {
+			return nil, fmt.Errorf("invalid options: %w", err)
 		}
+		return nil, fmt.Errorf("invalid options for %s: %w", filename, err)
+	}
 
-		ast.Inspect(f, func(n ast.Node) bool {
-			h.find(pass, n)
-			return true
-		})
+	return h, nil
+}
 
-		h.Process(pass)
+func newHelper(filename string, options ...Option) *Helper {
+	h := &Helper{
+		filename: filename,
+		style:    DefaultStyle,
+		align:    true,
+	}
+	for _, opt := range options {
+		opt(h)
 	}
+
+	return h
 }
 
 type Helper struct {
+	filename string // the file being processed.
+
 	style Style
 
 	align         bool     // whether enable tags align.
 	sort          bool     // whether enable tags sort.
 	fixedTagOrder []string // the order of tags, the other tags will be sorted by name.
+	duplicateKeys []string // the tag keys checked for duplicate serialized names.
+	gormCheck     bool     // whether validate gorm tags.
+	gormCanonical bool     // whether rewrite gorm tags in canonical form.
+
+	validateCheck      bool     // whether validate go-playground/validator tags.
+	validateCustomTags []string // the custom tags registered to the validator.
+
+	allowedKeys []string          // the tag keys allowed, any key is allowed if empty.
+	deniedKeys  map[string]string // the tag keys denied, with the message why it's denied.
+
+	redundantKeys []string // the tag keys checked for tags equal to the default behavior.
+	unalignedKeys []string // the tag keys never padded, placed at the end of the tags.
+
+	generated    bool     // whether check generated files.
+	includeFiles []string // the glob patterns of the files checked, all files are checked if empty.
+	excludeFiles []string // the glob patterns of the files skipped.
+
+	includeTypes    []string                   // the regexps of the type names aligned, all types are aligned if empty.
+	excludeTypes    []string                   // the regexps of the type names not aligned.
+	includePackages []string                   // the regexps of the package paths aligned, all packages are aligned if empty.
+	excludePackages []string                   // the regexps of the package paths not aligned.
+	typeNames       map[*ast.StructType]string // the name of the type declaration which the struct belongs to.
+
+	changedLines func(filename string, line int) bool // reports whether the line is changed, all lines are if nil.
+	rewriting    bool                                 // whether the structs of the file matched by the type filters are rewritten.
+	structs      int                                  // the number of structs found.
 
 	singleFields            []*ast.Field
 	consecutiveFieldsGroups [][]*ast.Field // fields in this group, must be consecutive in struct.
+
+	structOverrides map[*ast.StructType]*structOverride // the settings overridden by directives on the type declaration.
+	directiveErrors []directiveError                    // the errors of the directives overriding the settings.
+	fieldOverrides  map[*ast.Field]*structOverride      // the settings overridden for the fields of such structs.
 }
 
-func (w *Helper) find(pass *analysis.Pass, n ast.Node) {
-	v, ok := n.(*ast.StructType)
-	if !ok {
-		return
+// skipped reports whether the file is skipped by the generated files and file patterns settings.
+func (w *Helper) skipped(fset *token.FileSet, f *ast.File) bool {
+	// files processed by cgo have the generated comment of cgo, but a line directive to their source.
+	processed := fset.PositionFor(f.Pos(), false).Filename != w.filename
+	if !w.generated && ast.IsGenerated(f) && !processed {
+		return true
 	}
 
-	fields := v.Fields.List
-	if len(fields) == 0 {
-		return
+	match := func(pattern string) bool { return matchFile(pattern, w.filename) }
+	if len(w.includeFiles) > 0 && !slices.ContainsFunc(w.includeFiles, match) {
+		return true
 	}
 
-	fs := make([]*ast.Field, 0)
-	split := func() {
-		n := len(fs)
-		if n > 1 {
-			w.consecutiveFieldsGroups = append(w.consecutiveFieldsGroups, fs)
-		} else if n == 1 {
-			w.singleFields = append(w.singleFields, fs[0])
-		}
+	return slices.ContainsFunc(w.excludeFiles, match)
+}
 
-		fs = nil
+// Process reports the tags found which are not formatted, with the fixes.
+func (w *Helper) Process(pass *analysis.Pass) {
+	for _, e := range w.directiveErrors {
+		if w.changed(pass.Fset, e.directive.comment) {
+			w.reportDirective(pass, e.directive, e.msg)
+		}
 	}
 
-	for i, field := range fields {
-		if field.Tag == nil {
-			// field without tags
-			split()
+	for _, group := range w.Groups() {
+		if !w.groupChanged(pass.Fset, group) {
 			continue
 		}
-
-		if i > 0 {
-			if fields[i-1].Tag == nil {
-				// if previous filed do not have a tag
-				fs = append(fs, field)
-				continue
-			}
-			preLineNum := pass.Fset.Position(fields[i-1].Tag.Pos()).Line
-			lineNum := pass.Fset.Position(field.Tag.Pos()).Line
-			if lineNum-preLineNum > 1 {
-				// fields with tags are not consecutive, including two case:
-				// 1. splited by lines
-				// 2. splited by a struct
-				split()
-
-				// check if the field is a struct
-				if _, ok := field.Type.(*ast.StructType); ok {
-					continue
-				}
-			}
+		for _, tag := range group.Tags {
+			w.report(pass, tag)
 		}
-
-		fs = append(fs, field)
 	}
-
-	split()
 }
 
-func (w *Helper) report(pass *analysis.Pass, field *ast.Field, msg, replaceStr string) {
+func (w *Helper) report(pass *analysis.Pass, tag FieldTag) {
+	edit := tag.Edit()
 	pass.Report(analysis.Diagnostic{
-		Pos:     field.Tag.Pos(),
-		End:     field.Tag.End(),
-		Message: msg,
+		Pos:      tag.Field.Tag.Pos(),
+		End:      tag.Field.Tag.End(),
+		Category: tag.Rule,
+		Message:  tag.Message,
 		SuggestedFixes: []analysis.SuggestedFix{
 			{
-				Message: msg,
-				TextEdits: []analysis.TextEdit{
-					{
-						Pos:     field.Tag.Pos(),
-						End:     field.Tag.End(),
-						NewText: []byte(replaceStr),
-					},
-				},
+				Message:   tag.Message,
+				TextEdits: []analysis.TextEdit{{Pos: edit.Pos, End: edit.End, NewText: edit.NewText}},
 			},
 		},
 	})
 }
 
-//nolint:gocognit,gocyclo,nestif
-func (w *Helper) Process(pass *analysis.Pass) {
-	// process grouped fields
-	for _, fields := range w.consecutiveFieldsGroups {
-		offsets := make([]int, len(fields))
-
-		var maxTagNum int
-		var tagsGroup, notSortedTagsGroup [][]*structtag.Tag
-
-		var uniqueKeys []string
-		addKey := func(k string) {
-			for _, key := range uniqueKeys {
-				if key == k {
-					return
-				}
-			}
-			uniqueKeys = append(uniqueKeys, k)
-		}
-
-		for i := 0; i < len(fields); {
-			field := fields[i]
-			column := pass.Fset.Position(field.Tag.Pos()).Column - 1
-			offsets[i] = column
-
-			tag, err := strconv.Unquote(field.Tag.Value)
-			if err != nil {
-				// if tag value is not a valid string, report it directly
-				w.report(pass, field, errTagValueSyntax, field.Tag.Value)
-				fields = removeField(fields, i)
-				continue
-			}
-
-			tags, err := structtag.Parse(tag)
-			if err != nil {
-				// if tag value is not a valid struct tag, report it directly
-				w.report(pass, field, err.Error(), field.Tag.Value)
-				fields = removeField(fields, i)
-				continue
-			}
-
-			maxTagNum = max(maxTagNum, tags.Len())
-
-			if w.sort {
-				cp := make([]*structtag.Tag, tags.Len())
-				for i, tag := range tags.Tags() {
-					cp[i] = tag
-				}
-				notSortedTagsGroup = append(notSortedTagsGroup, cp)
-				sortTags(w.fixedTagOrder, tags)
-			}
-			for _, t := range tags.Tags() {
-				addKey(t.Key)
-			}
-			tagsGroup = append(tagsGroup, tags.Tags())
-
-			i++
-		}
-
-		if w.sort && StrictStyle == w.style {
-			sortKeys(w.fixedTagOrder, uniqueKeys)
-			maxTagNum = len(uniqueKeys)
-		}
-
-		// record the max length of each column tag
-		type tagLen struct {
-			Key string // present only when sort enabled
-			Len int
-		}
-		tagMaxLens := make([]tagLen, maxTagNum)
-		for j := 0; j < maxTagNum; j++ {
-			var maxLength int
-			var key string
-			for i := 0; i < len(tagsGroup); i++ {
-				if w.style == StrictStyle {
-					key = uniqueKeys[j]
-					// search by key
-					for _, tag := range tagsGroup[i] {
-						if tag.Key == key {
-							maxLength = max(maxLength, len(tag.String()))
-							break
-						}
-					}
-				} else {
-					if len(tagsGroup[i]) <= j {
-						// in case of index out of range
-						continue
-					}
-					maxLength = max(maxLength, len(tagsGroup[i][j].String()))
-				}
-			}
-			tagMaxLens[j] = tagLen{key, maxLength}
-		}
-
-		for i, field := range fields {
-			tags := tagsGroup[i]
-
-			var newTagStr string
-			if w.align {
-				// if align enabled, align tags.
-				newTagBuilder := strings.Builder{}
-				for i, n := 0, 0; i < len(tags) && n < len(tagMaxLens); {
-					tag := tags[i]
-					var format string
-					if w.style == StrictStyle {
-						if tagMaxLens[n].Key == tag.Key {
-							// match
-							format = alignFormat(tagMaxLens[n].Len + 1) // with an extra space
-							newTagBuilder.WriteString(fmt.Sprintf(format, tag.String()))
-							i++
-							n++
-						} else {
-							// tag missing
-							format = alignFormat(tagMaxLens[n].Len + 1)
-							newTagBuilder.WriteString(fmt.Sprintf(format, ""))
-							n++
-						}
-					} else {
-						format = alignFormat(tagMaxLens[n].Len + 1) // with an extra space
-						newTagBuilder.WriteString(fmt.Sprintf(format, tag.String()))
-						i++
-						n++
-					}
-				}
-				newTagStr = newTagBuilder.String()
-			} else {
-				// otherwise check if tags order changed
-				if w.sort && reflect.DeepEqual(notSortedTagsGroup[i], tags) {
-					// if tags order not changed, do nothing
-					continue
-				}
-				tagsStr := make([]string, len(tags))
-				for i, tag := range tags {
-					tagsStr[i] = tag.String()
-				}
-				newTagStr = strings.Join(tagsStr, " ")
-			}
-
-			unquoteTag := strings.TrimRight(newTagStr, " ")
-			// unquoteTag := newTagStr
-			newTagValue := fmt.Sprintf("`%s`", unquoteTag)
-			if field.Tag.Value == newTagValue {
-				// nothing changed
-				continue
-			}
-
-			msg := "tag is not aligned, should be: " + unquoteTag
-
-			w.report(pass, field, msg, newTagValue)
-		}
+// splitUnaligned splits the tags excluded from alignment, which are placed at the end in their order.
+func (w *Helper) splitUnaligned(tags []*structtag.Tag) (aligned, unaligned []*structtag.Tag) {
+	if len(w.unalignedKeys) == 0 {
+		return tags, nil
 	}
 
-	// process single fields
-	for _, field := range w.singleFields {
-		tag, err := strconv.Unquote(field.Tag.Value)
-		if err != nil {
-			w.report(pass, field, errTagValueSyntax, field.Tag.Value)
-			continue
-		}
-
-		tags, err := structtag.Parse(tag)
-		if err != nil {
-			w.report(pass, field, err.Error(), field.Tag.Value)
-			continue
-		}
-		originalTags := append([]*structtag.Tag(nil), tags.Tags()...)
-		if w.sort {
-			sortTags(w.fixedTagOrder, tags)
-		}
-
-		newTagValue := fmt.Sprintf("`%s`", tags.String())
-		if reflect.DeepEqual(originalTags, tags.Tags()) && field.Tag.Value == newTagValue {
-			// if tags order not changed, do nothing
-			continue
+	for _, tag := range tags {
+		if slices.Contains(w.unalignedKeys, tag.Key) {
+			unaligned = append(unaligned, tag)
+		} else {
+			aligned = append(aligned, tag)
 		}
+	}
 
-		msg := "tag is not aligned , should be: " + tags.String()
+	return aligned, unaligned
+}
 
-		w.report(pass, field, msg, newTagValue)
+// joinTags appends the tags to prefix separated by a single space.
+func joinTags(prefix string, tags []*structtag.Tag) string {
+	parts := make([]string, 0, len(tags)+1)
+	if prefix != "" {
+		parts = append(parts, prefix)
 	}
+	for _, tag := range tags {
+		parts = append(parts, tag.String())
+	}
+
+	return strings.Join(parts, " ")
 }
 
 // sortTags sorts tags by fixed order.
@@ -376,14 +335,6 @@ func alignFormat(length int) string {
 	return "%" + fmt.Sprintf("-%ds", length)
 }
 
-func removeField(fields []*ast.Field, index int) []*ast.Field {
-	if index < 0 || index >= len(fields) {
-		return fields
-	}
-
-	return append(fields[:index], fields[index+1:]...)
-}
-
 func getFilename(fset *token.FileSet, file *ast.File) string {
 	filename := fset.PositionFor(file.Pos(), true).Filename
 	if !strings.HasSuffix(filename, ".go") {
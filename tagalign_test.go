@@ -1,10 +1,20 @@
 package tagalign
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/alfatraining/structtag"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
 )
 
@@ -46,6 +56,77 @@ func TestAnalyzer(t *testing.T) {
 			desc: "bad syntax tag",
 			dir:  "bad_syntax_tag",
 		},
+		{
+			desc: "gorm check",
+			dir:  "gorm",
+			opts: []Option{WithGormCheck(true)},
+		},
+		{
+			desc: "validate check",
+			dir:  "validate",
+			opts: []Option{WithValidateCheck("is_code")},
+		},
+		{
+			desc: "allowed and denied keys",
+			dir:  "keys",
+			opts: []Option{
+				WithAllowedKeys("json", "yaml"),
+				WithDeniedKeys(map[string]string{"bson": "mongo is not used in this service", "xml": ""}),
+			},
+		},
+		{
+			desc: "redundant tags",
+			dir:  "redundant",
+			opts: []Option{WithAlign(false), WithRedundantCheck("json", "yaml")},
+		},
+		{
+			desc: "redundant tags removed by align",
+			dir:  "redundant_align",
+			opts: []Option{WithRedundantCheck("json")},
+		},
+		{
+			desc: "redundant tags of excluded types",
+			dir:  "redundant_filtered",
+			opts: []Option{WithRedundantCheck("json"), WithExcludeTypes("^Internal")},
+		},
+		{
+			desc: "ignore directives",
+			dir:  "ignore",
+		},
+		{
+			desc: "override by directives",
+			dir:  "override",
+			opts: []Option{WithSort("json", "yaml"), WithUnalignedKeys("validate")},
+		},
+		{
+			desc: "skip generated files",
+			dir:  "generated",
+		},
+		{
+			desc: "include and exclude files",
+			dir:  "files/...",
+			opts: []Option{WithIncludeFiles("files/internal/**"), WithExcludeFiles("*_gen.go")},
+		},
+		{
+			desc: "type filters",
+			dir:  "types",
+			opts: []Option{WithIncludeTypes(".*(Request|Response)$"), WithExcludeTypes("^Internal")},
+		},
+		{
+			desc: "package filters",
+			dir:  "packages/...",
+			opts: []Option{WithIncludePackages("^packages/"), WithExcludePackages("/internal/")},
+		},
+		{
+			desc: "unaligned keys",
+			dir:  "unaligned",
+			opts: []Option{WithSort("json", "yaml"), WithUnalignedKeys("validate", "swaggertype")},
+		},
+		{
+			desc: "duplicate name",
+			dir:  "duplicate_name",
+			opts: []Option{WithAlign(false), WithDuplicateNameCheck("json", "yaml")},
+		},
 	}
 
 	for _, test := range testCases {
@@ -57,6 +138,13 @@ func TestAnalyzer(t *testing.T) {
 	}
 }
 
+func TestAnalyzer_newFromPatch(t *testing.T) {
+	a := NewAnalyzer()
+	require.NoError(t, a.Flags.Set("new-from-patch", filepath.Join("testdata", "newlines.patch")))
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, "newlines")
+}
+
 func TestAnalyzer_cgo(t *testing.T) {
 	a := NewAnalyzer()
 
@@ -80,3 +168,229 @@ func Test_sortTags(t *testing.T) {
 	assert.Equal(t, "gorm", tags.Tags()[4].Key)
 	assert.Equal(t, "zip", tags.Tags()[5].Key)
 }
+
+func Test_canonicalGormTag(t *testing.T) {
+	assert.Equal(t, "column:foo;type:varchar(100);not null;index:idx_name,unique",
+		canonicalGormTag(" type:varchar(100) ;NOT NULL;column:foo;index:idx_name,unique;"))
+	assert.Equal(t, `default:a\;b;foo`, canonicalGormTag(`foo;default:a\;b`))
+}
+
+func Test_tagValueOffset(t *testing.T) {
+	tag := `json:"foo" gorm:"column:foo"`
+	assert.Equal(t, 6, tagValueOffset(tag, "json"))
+	assert.Equal(t, 17, tagValueOffset(tag, "gorm"))
+	assert.Equal(t, -1, tagValueOffset(tag, "yaml"))
+	assert.Equal(t, -1, tagValueOffset(`json:"a\"b"`, "json"))
+}
+
+func TestAnalyzer_tagErrorPosition(t *testing.T) {
+	a := NewAnalyzer(WithAlign(false), WithValidateCheck("is_code"))
+
+	results := analysistest.Run(t, analysistest.TestData(), a, "validate")
+	var found bool
+	for _, result := range results {
+		for _, d := range result.Diagnostics {
+			if d.Message != `unknown validate tag "requried"` {
+				continue
+			}
+			pos := result.Pass.Fset.Position(d.Pos)
+			end := result.Pass.Fset.Position(d.End)
+			assert.Equal(t, 35, pos.Column)
+			assert.Equal(t, 43, end.Column)
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
+
+func Test_suggestKey(t *testing.T) {
+	keys := []string{"json", "yaml", "xml", "validate"}
+	assert.Equal(t, "json", suggestKey("josn", keys))
+	assert.Equal(t, "yaml", suggestKey("yml", keys))
+	assert.Equal(t, "validate", suggestKey("valdiate", keys))
+	assert.Equal(t, "", suggestKey("toml", keys))
+	assert.Equal(t, "", suggestKey("x", keys))
+}
+
+func TestAnalyzer_flags(t *testing.T) {
+	a := NewAnalyzer()
+	require.NoError(t, a.Flags.Set("align", "false"))
+	require.NoError(t, a.Flags.Set("sort", "true"))
+	require.NoError(t, a.Flags.Set("order", "xml,json,yaml"))
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), a, "sortorder")
+}
+
+func TestLinter_Options(t *testing.T) {
+	l := NewLinter(WithSort("json"), WithAlign(false))
+	require.NoError(t, l.Analyzer().Flags.Set("align", "true"))
+	require.NoError(t, l.Analyzer().Flags.Set("order", "xml,json"))
+
+	options, err := l.Options(filepath.Join(t.TempDir(), "example.go"))
+	require.NoError(t, err)
+
+	h := newHelper("", options...)
+	assert.True(t, h.align)
+	assert.True(t, h.sort)
+	assert.Equal(t, []string{"xml", "json"}, h.fixedTagOrder)
+}
+
+func TestLinter_Validate(t *testing.T) {
+	l := NewLinter(WithSort())
+	require.NoError(t, l.Validate())
+
+	require.NoError(t, l.Analyzer().Flags.Set("strict", "true"))
+	require.NoError(t, l.Analyzer().Flags.Set("align", "false"))
+	assert.EqualError(t, l.Validate(), "invalid options: strict style requires align and sort")
+}
+
+func TestAnalyzer_orderWithoutSort(t *testing.T) {
+	a := NewAnalyzer()
+	require.NoError(t, a.Flags.Set("order", "json,yaml"))
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "/src/example.go", "package example\n", 0)
+	require.NoError(t, err)
+	pass := &analysis.Pass{
+		Fset:   fset,
+		Files:  []*ast.File{file},
+		Pkg:    types.NewPackage("example", "example"),
+		Report: func(analysis.Diagnostic) {},
+	}
+	_, err = a.Run(pass)
+	assert.ErrorContains(t, err, "order requires sort")
+}
+
+func TestAnalyzer_deniedKeysFlag(t *testing.T) {
+	a := NewAnalyzer()
+	require.NoError(t, a.Flags.Set("allowed-keys", "json,yaml"))
+	require.NoError(t, a.Flags.Set("denied-keys", "bson=mongo is not used in this service,xml"))
+
+	analysistest.Run(t, analysistest.TestData(), a, "keys")
+}
+
+func TestValidateOptions(t *testing.T) {
+	tests := []struct {
+		desc string
+		opts []Option
+		err  string
+	}{
+		{desc: "default"},
+		{desc: "strict with align and sort", opts: []Option{WithSort("json"), WithStrictStyle()}},
+		{desc: "strict without sort", opts: []Option{WithStrictStyle()}, err: "strict style requires align and sort"},
+		{desc: "strict without align", opts: []Option{WithAlign(false), WithSort(), WithStrictStyle()}, err: "strict style requires align and sort"},
+		{desc: "duplicate order", opts: []Option{WithSort("json", "yaml", "json")}, err: `duplicate tag key "json" in order`},
+		{desc: "invalid order", opts: []Option{WithSort("json", "x:y")}, err: `invalid tag key "x:y" in order`},
+		{desc: "empty order", opts: []Option{WithSort("")}, err: `invalid tag key "" in order`},
+		{desc: "order not allowed", opts: []Option{WithSort("json", "yaml"), WithAllowedKeys("json")}, err: `tag key "yaml" in order is not allowed`},
+		{desc: "order denied", opts: []Option{WithSort("bson"), WithDeniedKeys(map[string]string{"bson": ""})}, err: `tag key "bson" in order is denied`},
+		{
+			desc: "allowed and denied",
+			opts: []Option{WithAllowedKeys("json"), WithDeniedKeys(map[string]string{"json": ""})},
+			err:  `tag key "json" is both allowed and denied`,
+		},
+		{desc: "invalid file pattern", opts: []Option{WithExcludeFiles("gen/[a-")}, err: `invalid file pattern "gen/[a-"`},
+		{desc: "invalid type pattern", opts: []Option{WithIncludeTypes("(Request")}, err: `invalid pattern "(Request"`},
+		{desc: "order and unaligned", opts: []Option{WithSort("json"), WithUnalignedKeys("json")}, err: `tag key "json" is both in order and unaligned`},
+		{desc: "unsupported redundant key", opts: []Option{WithRedundantCheck("gorm")}, err: `redundant check doesn't support tag key "gorm"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			err := ValidateOptions(test.opts...)
+			if test.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, test.err)
+		})
+	}
+}
+
+func Test_parsePatch(t *testing.T) {
+	patch := `diff --git a/foo.go b/foo.go
+--- a/foo.go
++++ b/foo.go
+@@ -1,4 +1,5 @@
+ package foo
+-var a = 1
++var a = 2
++var b = 3
+ 
+ var c = 4
+@@ -10 +11,0 @@
+-var d = 5
+\ No newline at end of file
+diff --git a/bar.go b/bar.go
+deleted file mode 100644
+--- a/bar.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package bar
+--- /dev/null
++++ baz.go	2024-01-01 00:00:00
+@@ -0,0 +1,2 @@
++package baz
++
+`
+	changed, err := parsePatch(strings.NewReader(patch), "/src")
+	require.NoError(t, err)
+
+	assert.Equal(t, changedLines{
+		filepath.FromSlash("/src/foo.go"): {2: true, 3: true},
+		filepath.FromSlash("/src/baz.go"): {1: true, 2: true},
+	}, changed)
+	assert.True(t, changed.contains(filepath.FromSlash("/src/foo.go"), 3))
+	assert.False(t, changed.contains(filepath.FromSlash("/src/foo.go"), 4))
+
+	_, err = parsePatch(strings.NewReader("+++ b/foo.go\n@@ -1 @@\n"), "/src")
+	assert.ErrorContains(t, err, "invalid hunk header")
+}
+
+func Test_loadRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(name, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte(content), 0o644))
+	}
+
+	git("init", "-q")
+	write("foo.go", "package foo\n\nvar a = 1\n")
+	write(".gitignore", "ignored.go\n")
+	git("add", ".")
+	git("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init")
+
+	write("foo.go", "package foo\n\nvar a = 2\n")
+	write("sub/bar.go", "package bar\n")
+	write("ignored.go", "package foo\n")
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(filepath.Join(root, "sub")))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	changed, err := loadRevision("HEAD")
+	require.NoError(t, err)
+
+	assert.Equal(t, changedLines{
+		filepath.Join(root, "foo.go"):        {3: true},
+		filepath.Join(root, "sub", "bar.go"): nil,
+	}, changed)
+	assert.True(t, changed.contains(filepath.Join(root, "sub", "bar.go"), 1))
+	assert.False(t, changed.contains(filepath.Join(root, "foo.go"), 1))
+	assert.False(t, changed.contains(filepath.Join(root, "ignored.go"), 1))
+
+	_, err = loadRevision("unknown")
+	assert.ErrorContains(t, err, "can't diff with revision unknown")
+}
@@ -0,0 +1,59 @@
+package duplicatename
+
+type Base struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Audit struct {
+	Name string `json:"name"`
+}
+
+type SameDepth struct {
+	ID    int `json:"id"` // want `json name "id" is used by ID, Identifier at the same depth, all of them are ignored`
+	Ident int
+	// Identifier is serialized as "id" too.
+	Identifier int `json:"id"` // want `json name "id" is used by ID, Identifier at the same depth, all of them are ignored`
+	Ignored    int `json:"-"`
+	Other      int `json:"-"`
+}
+
+type Shadowing struct {
+	Base
+	ID int `json:"id"` // want `json name "id" of ID shadows Base.ID`
+}
+
+type Promoted struct {
+	Base  // want `json name "name" is used by Base.Name, Audit.Name`
+	Audit // want `json name "name" is used by Base.Name, Audit.Name`
+}
+
+type TaggedWins struct {
+	Name  string `json:"Title"` // want `json name "Title" of Name shadows Title`
+	Title string
+}
+
+type unexported struct {
+	id int
+}
+
+type YAMLOnly struct {
+	unexported
+	Foo string `json:"foo" yaml:"bar"` // want `yaml name "bar" is duplicated by Foo, Bar, encoding fails`
+	Bar string `json:"bar" yaml:"bar"` // want `yaml name "bar" is duplicated by Foo, Bar, encoding fails`
+}
+
+type Inlined struct {
+	Base `yaml:",inline"` // want `yaml name "name" is duplicated by Name, Base.Name, encoding fails`
+	Name string `json:"title"` // want `yaml name "name" is duplicated by Name, Base.Name, encoding fails`
+}
+
+type Lowercase struct {
+	Name  string // want `yaml name "name" is duplicated by Title, Name, encoding fails`
+	Title string `yaml:"name"` // want `yaml name "name" is duplicated by Title, Name, encoding fails`
+}
+
+type NotInlined struct {
+	Base
+	ID int `json:"-" yaml:"id"`
+}
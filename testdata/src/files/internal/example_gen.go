@@ -0,0 +1,6 @@
+package internal
+
+type Generated struct {
+	Foo int `json:"foo" validate:"required"`
+	FooBar int `json:"foo_bar" validate:"required"`
+}
@@ -0,0 +1,6 @@
+package generated
+
+type Example struct {
+	Foo int `json:"foo" validate:"required"` // want `tag is not aligned, should be: json:"foo"     validate:"required"`
+	FooBar int `json:"foo_bar" validate:"required"`
+}
@@ -0,0 +1,15 @@
+package gorm
+
+type User struct {
+	ID    uint   `gorm:"primaryKey;autoIncrement"  json:"id"`                 // want `tag is not aligned, should be: gorm:"primaryKey;autoIncrement"               json:"id"`
+	Name  string `gorm:"type:varchar(100); column:name;not null" json:"name"` // want `tag is not aligned, should be: gorm:"column:name;type:varchar\(100\);not null" json:"name"`
+	Email string `gorm:"uniqueIndex:idx_email;size:255" json:"email"`         // want `tag is not aligned, should be: gorm:"size:255;uniqueIndex:idx_email"         json:"email"`
+}
+
+type Invalid struct {
+	Foo string `gorm:"colum:foo"`                    // want `unknown gorm directive "colum"`
+	Bar string `gorm:"column:bar;COLUMN:baz"`        // want `duplicate gorm directive "COLUMN"` `tag is not aligned, should be: gorm:"column:bar;column:baz"`
+	Baz string `gorm:"size:abc;primaryKey:false"`    // want `gorm directive "size" requires an integer value, got "abc"`
+	Qux string `gorm:"type;index:idx_a;index:idx_b"` // want `gorm directive "type" requires a value`
+	Quu string `gorm:"unique:yes;embedded:true"`     // want `gorm directive "unique" requires a boolean value, got "yes"` `gorm directive "embedded" does not take a value`
+}
@@ -0,0 +1,27 @@
+package ignore
+
+//tagalign:ignore the table layout is kept by hand
+type Table struct {
+	Foo    int    `json:"foo"        validate:"required"`
+	FooBar string `json:"foo_bar"  validate:"required"`
+}
+
+type (
+	//tagalign:ignore
+	Ignored struct {
+		Foo int `json:"foo"   yaml:"foo"`
+	}
+
+	NotIgnored struct {
+		Foo int `json:"foo"   yaml:"foo"` // want `tag is not aligned , should be: json:"foo" yaml:"foo"`
+	}
+)
+
+type Fields struct {
+	Foo    int    `json:"foo" validate:"required"` // want `tag is not aligned, should be: json:"foo"     validate:"required"`
+	FooBar string `json:"foo_bar" validate:"required"`
+	//tagalign:ignore
+	Bar    int    `json:"bar"    validate:"required"`
+	BarFoo string `json:"bar_foo"  validate:"required"` //tagalign:ignore
+	Baz    int    `json:"baz"  validate:"required"`     // want `tag is not aligned , should be: json:"baz" validate:"required"`
+}
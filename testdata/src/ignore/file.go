@@ -0,0 +1,8 @@
+//tagalign:ignore
+
+package ignore
+
+type File struct {
+	Foo    int    `json:"foo"        validate:"required"`
+	FooBar string `json:"foo_bar"  validate:"required"`
+}
@@ -0,0 +1,10 @@
+package keys
+
+type Example struct {
+	Foo string `json:"foo" yaml:"foo"`
+	Bar string `josn:"bar" yaml:"bar"` // want `tag key "josn" is not allowed, did you mean "json"\?`
+	Baz string `json:"baz" yml:"baz"`  // want `tag key "yml" is not allowed, did you mean "yaml"\?`
+	Qux string `json:"qux" bson:"qux"` // want `tag key "bson" is denied: mongo is not used in this service`
+	Quu string `json:"quu" toml:"quu"` // want `tag key "toml" is not allowed`
+	Cor string `json:"cor" xml:"cor"`  // want `tag key "xml" is denied`
+}
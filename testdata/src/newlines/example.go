@@ -0,0 +1,14 @@
+package newlines
+
+type Changed struct {
+	Foo    int    `json:"foo" yaml:"foo"` // want `tag is not aligned, should be: json:"foo"     yaml:"foo"`
+	FooBar string `json:"foo_bar" yaml:"foo_bar"`
+	Bar    bool   `json:"bar" yaml:"bar"` // want `tag is not aligned, should be: json:"bar"     yaml:"bar"`
+}
+
+type Unchanged struct {
+	Foo    int    `json:"foo" yaml:"foo"`
+	FooBar string `json:"foo_bar" yaml:"foo_bar"`
+
+	Single string `yaml:"single" json:"single"`
+}
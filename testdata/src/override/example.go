@@ -0,0 +1,32 @@
+package override
+
+type API struct {
+	Name string `gorm:"column:name" json:"name"` // want `tag is not aligned, should be: json:"name" gorm:"column:name"`
+	Age  int    `json:"age" gorm:"column:age"`   // want `tag is not aligned, should be: json:"age"  gorm:"column:age"`
+}
+
+//tagalign:order=gorm,json
+type Model struct {
+	Name string `json:"name" gorm:"column:name"` // want `tag is not aligned, should be: gorm:"column:name" json:"name"`
+	Age  int    `gorm:"column:age" json:"age"`   // want `tag is not aligned, should be: gorm:"column:age"  json:"age"`
+}
+
+type (
+	//tagalign:style=strict
+	Strict struct {
+		Foo int `json:"foo" xml:"foo"`            // want `tag is not aligned, should be: json:"foo"            xml:"foo"`
+		Bar int `json:"bar" xml:"bar" yaml:"bar"` // want `tag is not aligned, should be: json:"bar" yaml:"bar" xml:"bar"`
+	}
+)
+
+//tagalign:order=json,,yaml // want `invalid tagalign:order: invalid tag key "" in order`
+//tagalign:style=loose // want `tagalign:style has an unknown style "loose", should be one of default and strict`
+//tagalign:sort // want `unknown tagalign directive "sort"`
+type Malformed struct {
+	Foo int `json:"foo" yaml:"foo"`
+}
+
+//tagalign:order=json,json,validate // want `invalid tagalign:order: duplicate tag key "json" in order; tag key "validate" is both in order and unaligned`
+type Unaligned struct {
+	Foo int `json:"foo" validate:"required"`
+}
@@ -0,0 +1,6 @@
+package api
+
+type User struct {
+	ID int `json:"id" yaml:"id"` // want `tag is not aligned, should be: json:"id"   yaml:"id"`
+	Name string `json:"name" yaml:"name"`
+}
@@ -0,0 +1,6 @@
+package db
+
+type User struct {
+	ID int `json:"id" gorm:"column:id"`
+	Name string `json:"name" gorm:"column:name"`
+}
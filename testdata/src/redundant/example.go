@@ -0,0 +1,10 @@
+package redundant
+
+type Example struct {
+	Name    string `json:"Name"`              // want `tag is redundant, same as the default name of the field: json:"Name"`
+	Age     int    `json:"Age"    yaml:"age"` // want `tag is redundant, same as the default name of the field: json:"Age" yaml:"age"`
+	Email   string `json:"Email,omitempty" yaml:"mail"`
+	Phone   string `json:"phone"  yaml:"phone"` // want `tag is redundant, same as the default name of the field: yaml:"phone"`
+	A, B    string `json:"A"`
+	Address string `validate:"required" json:"Address"` // want `tag is redundant, same as the default name of the field: json:"Address"`
+}
@@ -0,0 +1,7 @@
+package redundantalign
+
+type Example struct {
+	Name  string `json:"Name" validate:"required"` // want `tag is redundant, same as the default name of the field: json:"Name"` `tag is not aligned, should be: validate:"required"`
+	Email string `json:"email,omitempty" validate:"email"`
+	Phone string `json:"Phone"` // want `tag is redundant, same as the default name of the field: json:"Phone"` `tag is not aligned, should be: `
+}
@@ -0,0 +1,12 @@
+package redundantfiltered
+
+type Example struct {
+	Name  string `json:"Name" validate:"required"` // want `tag is redundant, same as the default name of the field: json:"Name"` `tag is not aligned, should be: validate:"required"`
+	Email string `json:"email,omitempty" validate:"email"`
+}
+
+type InternalExample struct {
+	Name  string `json:"Name" validate:"required"` // want `tag is redundant, same as the default name of the field: json:"Name"`
+	Email string `json:"email,omitempty" validate:"email"`
+	Phone string `json:"Phone"` // want `tag is redundant, same as the default name of the field: json:"Phone"`
+}
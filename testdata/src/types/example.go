@@ -0,0 +1,30 @@
+package types
+
+type CreateRequest struct {
+	Name string `json:"name" validate:"required"` // want `tag is not aligned, should be: json:"name"        validate:"required"`
+	Description string `json:"description" validate:"max=100"`
+	Owner struct {
+		ID int `json:"id" validate:"required"` // want `tag is not aligned, should be: json:"id"   validate:"required"`
+		Name string `json:"name" validate:"required"`
+	} `json:"owner"`
+}
+
+type CreateResponse struct {
+	ID int `json:"id" yaml:"id"` // want `tag is not aligned, should be: json:"id"      yaml:"id"`
+	Created bool `json:"created" yaml:"created"`
+}
+
+type InternalRequest struct {
+	Name string `json:"name" validate:"required"`
+	Description string `json:"description" validate:"max=100"`
+}
+
+type Model struct {
+	Name string `json:"name" gorm:"column:name"`
+	Description string `json:"description" gorm:"column:description"`
+}
+
+var config struct {
+	Name string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+}
@@ -0,0 +1,10 @@
+package unaligned
+
+type Example struct {
+	Foo    int    `validate:"required,min=1,max=100" json:"foo" yaml:"foo"` // want `tag is not aligned, should be: json:"foo"     yaml:"foo" validate:"required,min=1,max=100"`
+	FooBar string `json:"foo_bar" validate:"required" yaml:"foo_bar"`     // want `tag is not aligned, should be: json:"foo_bar" yaml:"foo_bar" validate:"required"`
+	Bar    bool   `json:"bar"     yaml:"bar"`
+	Baz    string `swaggertype:"string" json:"baz"`                        // want `tag is not aligned, should be: json:"baz" swaggertype:"string"`
+
+	Single string `validate:"required" json:"single"` // want `tag is not aligned , should be: json:"single" validate:"required"`
+}
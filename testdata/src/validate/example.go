@@ -0,0 +1,20 @@
+package validate
+
+type Request struct {
+	Name  string            `json:"name"  validate:"required,min=1,max=10"`
+	Kind  string            `json:"kind"  validate:"omitempty,oneof=a b c"`
+	Color string            `json:"color" validate:"hexcolor|rgb|rgba"`
+	Tags  map[string]string `json:"tags"  validate:"dive,keys,alpha,endkeys,required"`
+	Code  string            `json:"code"  validate:"required,is_code"`
+	Skip  string            `json:"skip"  validate:"-"`
+}
+
+type Invalid struct {
+	Foo string            `validate:"requried"`                 // want `unknown validate tag "requried"`
+	Bar string            `validate:"required,,max=1"`          // want `empty validate rule`
+	Baz string            `binding:"min"`                       // want `binding tag "min" requires a parameter`
+	Qux map[string]string `validate:"keys,alpha,endkeys"`       // want `validate tag "keys" must immediately follow "dive"`
+	Quu map[string]string `validate:"dive,keys,alpha"`          // want `validate tag "keys" without a corresponding "endkeys"`
+	Cor []string          `validate:"dive,alpha,endkeys"`       // want `validate tag "endkeys" without a corresponding "keys"`
+	Alt string            `validate:"omitempty|email,rgb||hsl"` // want `validate tag "omitempty" can't be used in an alternation` `empty validate rule`
+}
@@ -0,0 +1,134 @@
+package tagalign
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// validateTags lists the tags baked in go-playground/validator.
+var validateTags = []string{
+	// fields
+	"eqcsfield", "eqfield", "fieldcontains", "fieldexcludes", "gtcsfield", "gtecsfield", "gtefield", "gtfield",
+	"ltcsfield", "ltecsfield", "ltefield", "ltfield", "necsfield", "nefield",
+	// network
+	"cidr", "cidrv4", "cidrv6", "datauri", "fqdn", "hostname", "hostname_port", "hostname_rfc1123", "ip", "ip4_addr",
+	"ip6_addr", "ip_addr", "ipv4", "ipv6", "mac", "tcp4_addr", "tcp6_addr", "tcp_addr", "udp4_addr", "udp6_addr",
+	"udp_addr", "unix_addr", "uri", "url", "http_url", "url_encoded", "urn_rfc2141",
+	// strings
+	"alpha", "alphanum", "alphanumunicode", "alphaunicode", "ascii", "boolean", "contains", "containsany",
+	"containsrune", "endsnotwith", "endswith", "excludes", "excludesall", "excludesrune", "lowercase", "multibyte",
+	"number", "numeric", "printascii", "startsnotwith", "startswith", "uppercase",
+	// format
+	"base64", "base64url", "base64rawurl", "bic", "bcp47_language_tag", "btc_addr", "btc_addr_bech32",
+	"credit_card", "mongodb", "mongodb_connection_string", "cron", "spicedb", "datetime", "e164", "email",
+	"eth_addr", "hexadecimal", "hexcolor", "hsl", "hsla", "html", "html_encoded", "isbn", "isbn10", "isbn13",
+	"issn", "iso3166_1_alpha2", "iso3166_1_alpha3", "iso3166_1_alpha_numeric", "iso3166_2", "iso4217",
+	"iso4217_numeric", "json", "jwt", "latitude", "longitude", "luhn_checksum", "postcode_iso3166_alpha2",
+	"postcode_iso3166_alpha2_field", "rgb", "rgba", "ssn", "timezone", "uuid", "uuid3", "uuid3_rfc4122", "uuid4",
+	"uuid4_rfc4122", "uuid5", "uuid5_rfc4122", "uuid_rfc4122", "md4", "md5", "sha256", "sha384", "sha512",
+	"ripemd128", "ripemd160", "tiger128", "tiger160", "tiger192", "semver", "ulid", "cve", "ein",
+	// comparisons
+	"eq", "eq_ignore_case", "gt", "gte", "lt", "lte", "ne", "ne_ignore_case",
+	// other
+	"dir", "dirpath", "file", "filepath", "image", "isdefault", "len", "max", "min", "oneof", "oneofci", "required",
+	"required_if", "required_unless", "required_with", "required_with_all", "required_without",
+	"required_without_all", "excluded_if", "excluded_unless", "excluded_with", "excluded_with_all",
+	"excluded_without", "excluded_without_all", "unique", "validateFn",
+	// aliases
+	"iscolor", "country_code",
+	// control
+	"omitempty", "omitnil", "omitzero", "structonly", "nostructlevel",
+}
+
+// validateParamTags lists the tags which require a parameter.
+var validateParamTags = []string{
+	"eqcsfield", "eqfield", "fieldcontains", "fieldexcludes", "gtcsfield", "gtecsfield", "gtefield", "gtfield",
+	"ltcsfield", "ltecsfield", "ltefield", "ltfield", "necsfield", "nefield",
+	"contains", "containsany", "containsrune", "endsnotwith", "endswith", "excludes", "excludesall",
+	"excludesrune", "startsnotwith", "startswith", "datetime", "postcode_iso3166_alpha2_field",
+	"eq", "eq_ignore_case", "gt", "gte", "lt", "lte", "ne", "ne_ignore_case", "len", "max", "min", "oneof", "oneofci",
+	"required_if", "required_unless", "required_with", "required_with_all", "required_without",
+	"required_without_all", "excluded_if", "excluded_unless", "excluded_with", "excluded_with_all",
+	"excluded_without", "excluded_without_all",
+}
+
+// validateRule is a comma separated rule of a validate tag.
+type validateRule struct {
+	text   string
+	offset int // byte offset of the rule in the tag value.
+}
+
+func splitValidateRules(value, sep string, offset int) []validateRule {
+	var rules []validateRule
+	for {
+		text, rest, found := strings.Cut(value, sep)
+		rules = append(rules, validateRule{text: text, offset: offset})
+		if !found {
+			return rules
+		}
+		value = rest
+		offset += len(text) + len(sep)
+	}
+}
+
+// checkValidate reports syntax errors of a go-playground/validator tag, such as unknown tags,
+// missing parameters and unbalanced dive, keys and endkeys.
+func (w *Helper) checkValidate(pass *analysis.Pass, loc tagLocation, key, value string) {
+	if value == "-" {
+		return
+	}
+
+	report := func(r validateRule, msg string) {
+		w.reportTagError(pass, loc, r.offset, max(len(r.text), 1), RuleValidate, msg)
+	}
+
+	var prev string
+	var keys *validateRule
+	for _, r := range splitValidateRules(value, ",", 0) {
+		switch r.text {
+		case "":
+			report(r, fmt.Sprintf("empty %s rule", key))
+		case "dive":
+		case "keys":
+			if prev != "dive" {
+				report(r, fmt.Sprintf("%s tag \"keys\" must immediately follow \"dive\"", key))
+			}
+			if keys != nil {
+				report(r, fmt.Sprintf("%s tag \"keys\" is nested in \"keys\"", key))
+			}
+			keys = &r
+		case "endkeys":
+			if keys == nil {
+				report(r, fmt.Sprintf("%s tag \"endkeys\" without a corresponding \"keys\"", key))
+			}
+			keys = nil
+		default:
+			alternatives := splitValidateRules(r.text, "|", r.offset)
+			for _, alt := range alternatives {
+				w.checkValidateRule(report, key, alt, len(alternatives) > 1)
+			}
+		}
+		prev = r.text
+	}
+
+	if keys != nil {
+		report(*keys, fmt.Sprintf("%s tag \"keys\" without a corresponding \"endkeys\"", key))
+	}
+}
+
+func (w *Helper) checkValidateRule(report func(validateRule, string), key string, r validateRule, alternation bool) {
+	name, _, hasParam := strings.Cut(r.text, "=")
+	switch {
+	case name == "":
+		report(r, fmt.Sprintf("empty %s rule", key))
+	case alternation && slices.Contains([]string{"dive", "keys", "endkeys", "omitempty", "omitnil", "omitzero", "-"}, name):
+		report(r, fmt.Sprintf("%s tag %q can't be used in an alternation", key, name))
+	case !slices.Contains(validateTags, name) && !slices.Contains(w.validateCustomTags, name):
+		report(r, fmt.Sprintf("unknown %s tag %q", key, name))
+	case !hasParam && slices.Contains(validateParamTags, name):
+		report(r, fmt.Sprintf("%s tag %q requires a parameter", key, name))
+	}
+}